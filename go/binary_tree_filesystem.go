@@ -1,7 +1,17 @@
 package main
 
 import (
+	"bytes"
+	"cmp"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"path"
 	"sort"
 	"strings"
 	"time"
@@ -11,6 +21,7 @@ type FileNode struct {
 	name     string
 	isDir    bool
 	size     int64
+	content  []byte
 	modified time.Time
 	children map[string]*FileNode
 	parent   *FileNode
@@ -35,15 +46,43 @@ func NewFileSystem() *FileSystem {
 	return &FileSystem{root: root}
 }
 
+func normalizePath(path string) (string, error) {
+	var parts []string
+	for _, part := range strings.Split(path, "/") {
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			if len(parts) == 0 {
+				return "", fmt.Errorf("path escapes root: %s", path)
+			}
+			parts = parts[:len(parts)-1]
+		default:
+			parts = append(parts, part)
+		}
+	}
+	return "/" + strings.Join(parts, "/"), nil
+}
+
+func splitPath(path string) ([]string, error) {
+	normalized, err := normalizePath(path)
+	if err != nil {
+		return nil, err
+	}
+	if normalized == "/" {
+		return nil, nil
+	}
+	return strings.Split(normalized[1:], "/"), nil
+}
+
 func (fs *FileSystem) CreateDir(path string) error {
-	parts := strings.Split(strings.Trim(path, "/"), "/")
+	parts, err := splitPath(path)
+	if err != nil {
+		return err
+	}
 	current := fs.root
 	
 	for _, part := range parts {
-		if part == "" {
-			continue
-		}
-		
 		if child, exists := current.children[part]; exists {
 			if !child.isDir {
 				return fmt.Errorf("file exists with name %s", part)
@@ -60,24 +99,24 @@ func (fs *FileSystem) CreateDir(path string) error {
 }
 
 func (fs *FileSystem) CreateFile(path string, size int64) error {
-	lastSlash := strings.LastIndex(path, "/")
-	dirPath := path[:lastSlash]
-	fileName := path[lastSlash+1:]
+	dirPath := "/"
+	fileName := path
+	if lastSlash := strings.LastIndex(path, "/"); lastSlash >= 0 {
+		dirPath = path[:lastSlash]
+		fileName = path[lastSlash+1:]
+	}
 	
-	if dirPath == "" {
-		dirPath = "/"
+	if fileName == "" || fileName == "." || fileName == ".." {
+		return fmt.Errorf("invalid file name: %s", path)
 	}
 	
-	fs.CreateDir(dirPath)
+	if err := fs.CreateDir(dirPath); err != nil {
+		return err
+	}
 	
-	current := fs.root
-	if dirPath != "/" {
-		parts := strings.Split(strings.Trim(dirPath, "/"), "/")
-		for _, part := range parts {
-			if part != "" {
-				current = current.children[part]
-			}
-		}
+	current, err := fs.lookup(dirPath)
+	if err != nil {
+		return err
 	}
 	
 	if _, exists := current.children[fileName]; exists {
@@ -90,76 +129,467 @@ func (fs *FileSystem) CreateFile(path string, size int64) error {
 	return nil
 }
 
-func (fs *FileSystem) List(path string) []string {
+func (fs *FileSystem) WriteFile(path string, data []byte) error {
+	node, err := fs.lookup(path)
+	if err != nil {
+		if err := fs.CreateFile(path, 0); err != nil {
+			return err
+		}
+		if node, err = fs.lookup(path); err != nil {
+			return err
+		}
+	}
+	if node.isDir {
+		return fmt.Errorf("is a directory: %s", path)
+	}
+	
+	node.content = append([]byte(nil), data...)
+	node.size = int64(len(data))
+	node.modified = time.Now()
+	return nil
+}
+
+func (fs *FileSystem) ReadFile(path string) ([]byte, error) {
+	node, err := fs.lookup(path)
+	if err != nil {
+		return nil, err
+	}
+	if node.isDir {
+		return nil, fmt.Errorf("is a directory: %s", path)
+	}
+	return append([]byte(nil), node.content...), nil
+}
+
+var ErrNotExist = errors.New("no such file or directory")
+
+type FileInfo struct {
+	Name     string
+	IsDir    bool
+	Size     int64
+	Modified time.Time
+	Children int
+}
+
+func (fs *FileSystem) Stat(path string) (FileInfo, error) {
+	node, err := fs.lookup(path)
+	if err != nil {
+		return FileInfo{}, err
+	}
+	return FileInfo{
+		Name:     node.name,
+		IsDir:    node.isDir,
+		Size:     node.size,
+		Modified: node.modified,
+		Children: len(node.children),
+	}, nil
+}
+
+func (fs *FileSystem) lookup(path string) (*FileNode, error) {
+	parts, err := splitPath(path)
+	if err != nil {
+		return nil, err
+	}
+	
 	current := fs.root
-	if path != "/" {
-		parts := strings.Split(strings.Trim(path, "/"), "/")
-		for _, part := range parts {
-			if part != "" {
-				if child, exists := current.children[part]; exists {
-					current = child
-				} else {
-					return nil
-				}
+	for _, part := range parts {
+		child, exists := current.children[part]
+		if !exists {
+			return nil, fmt.Errorf("%w: %s", ErrNotExist, path)
+		}
+		current = child
+	}
+	return current, nil
+}
+
+func (fs *FileSystem) Delete(path string) error {
+	node, err := fs.lookup(path)
+	if err != nil {
+		return err
+	}
+	if node == fs.root {
+		return fmt.Errorf("cannot delete root directory")
+	}
+	
+	delete(node.parent.children, node.name)
+	node.parent.modified = time.Now()
+	node.parent = nil
+	return nil
+}
+
+func (fs *FileSystem) Copy(src, dst string) error {
+	source, err := fs.lookup(src)
+	if err != nil {
+		return err
+	}
+	if _, err := fs.lookup(dst); err == nil {
+		return fmt.Errorf("destination already exists: %s", dst)
+	}
+	
+	trimmed, err := normalizePath(dst)
+	if err != nil {
+		return err
+	}
+	lastSlash := strings.LastIndex(trimmed, "/")
+	dirPath := trimmed[:lastSlash+1]
+	name := trimmed[lastSlash+1:]
+	if name == "" {
+		return fmt.Errorf("invalid destination: %s", dst)
+	}
+	
+	if err := fs.CreateDir(dirPath); err != nil {
+		return err
+	}
+	parent, err := fs.lookup(dirPath)
+	if err != nil {
+		return err
+	}
+	
+	clone := copyNode(source, name)
+	clone.parent = parent
+	parent.children[name] = clone
+	return nil
+}
+
+func copyNode(node *FileNode, name string) *FileNode {
+	clone := NewFileNode(name, node.isDir, node.size)
+	clone.content = append([]byte(nil), node.content...)
+	for childName, child := range node.children {
+		childClone := copyNode(child, childName)
+		childClone.parent = clone
+		clone.children[childName] = childClone
+	}
+	return clone
+}
+
+var SkipDir = errors.New("skip this directory")
+
+func (fs *FileSystem) Walk(path string, fn func(fullPath string, node *FileNode) error) error {
+	node, err := fs.lookup(path)
+	if err != nil {
+		return err
+	}
+	start, _ := normalizePath(path)
+	
+	err = walkNode(start, node, fn)
+	if errors.Is(err, SkipDir) {
+		return nil
+	}
+	return err
+}
+
+func walkNode(fullPath string, node *FileNode, fn func(fullPath string, node *FileNode) error) error {
+	if err := fn(fullPath, node); err != nil {
+		if errors.Is(err, SkipDir) && node.isDir {
+			return nil
+		}
+		return err
+	}
+	
+	for _, name := range sortedChildNames(node) {
+		if err := walkNode(joinPath(fullPath, name), node.children[name], fn); err != nil {
+			if errors.Is(err, SkipDir) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func joinPath(dir, name string) string {
+	if dir == "/" {
+		return "/" + name
+	}
+	return dir + "/" + name
+}
+
+func sortedChildNames(node *FileNode) []string {
+	names := make([]string, 0, len(node.children))
+	for name := range node.children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (fs *FileSystem) DiskUsage(path string) (int64, error) {
+	var total int64
+	err := fs.Walk(path, func(fullPath string, node *FileNode) error {
+		if !node.isDir {
+			total += node.size
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func (fs *FileSystem) CountNodes(path string) (files int, dirs int, err error) {
+	start, err := fs.lookup(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	
+	err = fs.Walk(path, func(fullPath string, node *FileNode) error {
+		switch {
+		case node == start:
+			if !node.isDir {
+				files++
 			}
+		case node.isDir:
+			dirs++
+		default:
+			files++
+		}
+		return nil
+	})
+	return files, dirs, err
+}
+
+type FileSize struct {
+	Path string
+	Size int64
+}
+
+func (fs *FileSystem) LargestFiles(n int) []FileSize {
+	var files []FileSize
+	fs.Walk("/", func(fullPath string, node *FileNode) error {
+		if !node.isDir {
+			files = append(files, FileSize{Path: fullPath, Size: node.size})
 		}
+		return nil
+	})
+	
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].Size != files[j].Size {
+			return files[i].Size > files[j].Size
+		}
+		return files[i].Path < files[j].Path
+	})
+	
+	if n >= 0 && n < len(files) {
+		files = files[:n]
+	}
+	return files
+}
+
+func (fs *FileSystem) Find(pattern string) []string {
+	matches, _ := fs.FindErr(pattern)
+	return matches
+}
+
+func (fs *FileSystem) FindErr(pattern string) ([]string, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
 	}
 	
-	var result []string
-	for name := range current.children {
-		result = append(result, name)
+	var matches []string
+	fs.Walk("/", func(fullPath string, node *FileNode) error {
+		if node == fs.root {
+			return nil
+		}
+		if matched, _ := path.Match(pattern, node.name); matched {
+			matches = append(matches, fullPath)
+		}
+		return nil
+	})
+	
+	sort.Strings(matches)
+	return matches, nil
+}
+
+type fileNodeJSON struct {
+	Name     string         `json:"name"`
+	IsDir    bool           `json:"isDir"`
+	Size     int64          `json:"size"`
+	Content  []byte         `json:"content,omitempty"`
+	Modified time.Time      `json:"modified"`
+	Children []fileNodeJSON `json:"children,omitempty"`
+}
+
+func (node *FileNode) toJSON() fileNodeJSON {
+	snapshot := fileNodeJSON{
+		Name:     node.name,
+		IsDir:    node.isDir,
+		Size:     node.size,
+		Content:  node.content,
+		Modified: node.modified,
 	}
-	sort.Strings(result)
-	return result
+	for _, name := range sortedChildNames(node) {
+		snapshot.Children = append(snapshot.Children, node.children[name].toJSON())
+	}
+	return snapshot
+}
+
+func (snapshot fileNodeJSON) toNode(parent *FileNode) (*FileNode, error) {
+	if parent != nil && !parent.isDir {
+		return nil, fmt.Errorf("file %s cannot have children", parent.name)
+	}
+	
+	node := NewFileNode(snapshot.Name, snapshot.IsDir, snapshot.Size)
+	node.content = snapshot.Content
+	node.modified = snapshot.Modified
+	node.parent = parent
+	for _, childSnapshot := range snapshot.Children {
+		if _, exists := node.children[childSnapshot.Name]; exists {
+			return nil, fmt.Errorf("duplicate entry %s in %s", childSnapshot.Name, node.name)
+		}
+		child, err := childSnapshot.toNode(node)
+		if err != nil {
+			return nil, err
+		}
+		node.children[child.name] = child
+	}
+	return node, nil
+}
+
+func (fs *FileSystem) Export() ([]byte, error) {
+	return json.Marshal(fs.root.toJSON())
+}
+
+func Import(data []byte) (*FileSystem, error) {
+	var snapshot fileNodeJSON
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("invalid filesystem snapshot: %w", err)
+	}
+	if !snapshot.IsDir {
+		return nil, fmt.Errorf("invalid filesystem snapshot: root is not a directory")
+	}
+	
+	root, err := snapshot.toNode(nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filesystem snapshot: %w", err)
+	}
+	return &FileSystem{root: root}, nil
+}
+
+func (fs *FileSystem) lookupDir(path string) (*FileNode, error) {
+	node, err := fs.lookup(path)
+	if errors.Is(err, ErrNotExist) {
+		return nil, fmt.Errorf("no such directory: %s", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !node.isDir {
+		return nil, fmt.Errorf("not a directory: %s", path)
+	}
+	return node, nil
+}
+
+func (fs *FileSystem) List(path string) ([]string, error) {
+	current, err := fs.lookupDir(path)
+	if err != nil {
+		return nil, err
+	}
+	return sortedChildNames(current), nil
+}
+
+type SortField int
+
+const (
+	SortByName SortField = iota
+	SortBySize
+	SortByModified
+)
+
+type SortKey struct {
+	Field      SortField
+	Descending bool
+}
+
+func (fs *FileSystem) ListSorted(path string, by SortKey) ([]string, error) {
+	current, err := fs.lookupDir(path)
+	if err != nil {
+		return nil, err
+	}
+	
+	names := sortedChildNames(current)
+	less := func(a, b *FileNode) bool {
+		switch by.Field {
+		case SortBySize:
+			return a.size < b.size
+		case SortByModified:
+			return a.modified.Before(b.modified)
+		default:
+			return a.name < b.name
+		}
+	}
+	sort.SliceStable(names, func(i, j int) bool {
+		a, b := current.children[names[i]], current.children[names[j]]
+		if by.Descending {
+			return less(b, a)
+		}
+		return less(a, b)
+	})
+	return names, nil
 }
 
 func (fs *FileSystem) PrintTree(node *FileNode, indent string) {
 	if node == nil {
 		node = fs.root
 	}
-	
+	writeTree(os.Stdout, node, indent, -1)
+}
+
+func (fs *FileSystem) PrintTreeDepth(node *FileNode, maxDepth int) {
+	fs.FprintTreeDepth(os.Stdout, node, maxDepth)
+}
+
+func (fs *FileSystem) FprintTreeDepth(w io.Writer, node *FileNode, maxDepth int) {
+	if node == nil {
+		node = fs.root
+	}
+	writeTree(w, node, "", maxDepth)
+}
+
+func writeTree(w io.Writer, node *FileNode, indent string, remainingDepth int) {
 	nodeType := "DIR"
 	if !node.isDir {
 		nodeType = fmt.Sprintf("FILE (%d bytes)", node.size)
 	}
 	
-	fmt.Printf("%s%s [%s]\n", indent, node.name, nodeType)
+	fmt.Fprintf(w, "%s%s [%s]\n", indent, node.name, nodeType)
 	
-	var names []string
-	for name := range node.children {
-		names = append(names, name)
+	if remainingDepth == 0 {
+		if len(node.children) > 0 {
+			fmt.Fprintf(w, "%s  ...\n", indent)
+		}
+		return
 	}
-	sort.Strings(names)
 	
-	for _, name := range names {
-		fs.PrintTree(node.children[name], indent+"  ")
+	for _, name := range sortedChildNames(node) {
+		writeTree(w, node.children[name], indent+"  ", remainingDepth-1)
 	}
 }
 
-type BTreeNode struct {
-	keys     []int
-	values   []string
-	children []*BTreeNode
+type BTreeNode[K cmp.Ordered, V any] struct {
+	keys     []K
+	values   []V
+	children []*BTreeNode[K, V]
 	leaf     bool
 }
 
-type BTree struct {
-	root   *BTreeNode
+type BTree[K cmp.Ordered, V any] struct {
+	root   *BTreeNode[K, V]
 	degree int
+	count  int
 }
 
-func NewBTree(degree int) *BTree {
-	return &BTree{
-		root:   &BTreeNode{leaf: true},
+func NewBTree[K cmp.Ordered, V any](degree int) *BTree[K, V] {
+	return &BTree[K, V]{
+		root:   &BTreeNode[K, V]{leaf: true},
 		degree: degree,
 	}
 }
 
-func (bt *BTree) Search(key int) (string, bool) {
+func (bt *BTree[K, V]) Search(key K) (V, bool) {
 	return bt.searchNode(bt.root, key)
 }
 
-func (bt *BTree) searchNode(node *BTreeNode, key int) (string, bool) {
+func (bt *BTree[K, V]) searchNode(node *BTreeNode[K, V], key K) (V, bool) {
 	i := 0
 	for i < len(node.keys) && key > node.keys[i] {
 		i++
@@ -170,32 +600,186 @@ func (bt *BTree) searchNode(node *BTreeNode, key int) (string, bool) {
 	}
 	
 	if node.leaf {
-		return "", false
+		var zero V
+		return zero, false
 	}
 	
 	return bt.searchNode(node.children[i], key)
 }
 
-func (bt *BTree) Insert(key int, value string) {
+func (bt *BTree[K, V]) Insert(key K, value V) {
 	if bt.isFull(bt.root) {
-		newRoot := &BTreeNode{leaf: false}
+		newRoot := &BTreeNode[K, V]{leaf: false}
 		newRoot.children = append(newRoot.children, bt.root)
 		bt.splitChild(newRoot, 0)
 		bt.root = newRoot
 	}
 	bt.insertNonFull(bt.root, key, value)
+	bt.count++
+}
+
+var ErrDuplicateKey = errors.New("duplicate key")
+
+func (bt *BTree[K, V]) InsertUnique(key K, value V) error {
+	if _, exists := bt.Search(key); exists {
+		return fmt.Errorf("%w: %v", ErrDuplicateKey, key)
+	}
+	bt.Insert(key, value)
+	return nil
+}
+
+func (bt *BTree[K, V]) BulkLoad(pairs []BTreeEntry[K, V]) error {
+	for i := 1; i < len(pairs); i++ {
+		if pairs[i-1].Key == pairs[i].Key {
+			return fmt.Errorf("duplicate key %v at index %d", pairs[i].Key, i)
+		}
+		if pairs[i-1].Key > pairs[i].Key {
+			return fmt.Errorf("keys not sorted at index %d: %v > %v", i, pairs[i-1].Key, pairs[i].Key)
+		}
+	}
+	
+	keys := make([]K, len(pairs))
+	values := make([]V, len(pairs))
+	for i, pair := range pairs {
+		keys[i] = pair.Key
+		values[i] = pair.Value
+	}
+	
+	var children []*BTreeNode[K, V]
+	leaf := true
+	maxChildren := 2 * bt.degree
+	for {
+		groups := (len(keys) + maxChildren) / maxChildren
+		if groups <= 1 {
+			bt.root = &BTreeNode[K, V]{keys: keys, values: values, children: children, leaf: leaf}
+			break
+		}
+		
+		groupKeys := len(keys) - (groups - 1)
+		base, extra := groupKeys/groups, groupKeys%groups
+		
+		var nextKeys []K
+		var nextValues []V
+		var nextChildren []*BTreeNode[K, V]
+		keyIndex, childIndex := 0, 0
+		for g := 0; g < groups; g++ {
+			size := base
+			if g < extra {
+				size++
+			}
+			
+			node := &BTreeNode[K, V]{leaf: leaf}
+			node.keys = append([]K(nil), keys[keyIndex:keyIndex+size]...)
+			node.values = append([]V(nil), values[keyIndex:keyIndex+size]...)
+			if !leaf {
+				node.children = append([]*BTreeNode[K, V](nil), children[childIndex:childIndex+size+1]...)
+				childIndex += size + 1
+			}
+			keyIndex += size
+			nextChildren = append(nextChildren, node)
+			
+			if g < groups-1 {
+				nextKeys = append(nextKeys, keys[keyIndex])
+				nextValues = append(nextValues, values[keyIndex])
+				keyIndex++
+			}
+		}
+		
+		keys, values, children = nextKeys, nextValues, nextChildren
+		leaf = false
+	}
+	
+	bt.count = len(pairs)
+	return nil
+}
+
+func (bt *BTree[K, V]) Validate() error {
+	leafDepth := -1
+	return bt.validateNode(bt.root, nil, nil, 0, &leafDepth)
+}
+
+func (bt *BTree[K, V]) validateNode(node *BTreeNode[K, V], lower, upper *K, depth int, leafDepth *int) error {
+	maxKeys := 2*bt.degree - 1
+	if len(node.keys) > maxKeys {
+		return fmt.Errorf("node at depth %d has %d keys, max is %d", depth, len(node.keys), maxKeys)
+	}
+	if node != bt.root && len(node.keys) < bt.degree-1 {
+		return fmt.Errorf("node at depth %d has %d keys, min is %d", depth, len(node.keys), bt.degree-1)
+	}
+	if len(node.values) != len(node.keys) {
+		return fmt.Errorf("node at depth %d has %d keys but %d values", depth, len(node.keys), len(node.values))
+	}
+	
+	for i, key := range node.keys {
+		if i > 0 && node.keys[i-1] > key {
+			return fmt.Errorf("keys out of order at depth %d: %v > %v", depth, node.keys[i-1], key)
+		}
+		if lower != nil && key < *lower {
+			return fmt.Errorf("key %v at depth %d is below separator %v", key, depth, *lower)
+		}
+		if upper != nil && key > *upper {
+			return fmt.Errorf("key %v at depth %d is above separator %v", key, depth, *upper)
+		}
+	}
+	
+	if node.leaf {
+		if len(node.children) != 0 {
+			return fmt.Errorf("leaf at depth %d has %d children", depth, len(node.children))
+		}
+		if *leafDepth == -1 {
+			*leafDepth = depth
+		} else if *leafDepth != depth {
+			return fmt.Errorf("leaves at different depths: %d and %d", *leafDepth, depth)
+		}
+		return nil
+	}
+	
+	if len(node.children) != len(node.keys)+1 {
+		return fmt.Errorf("internal node at depth %d has %d keys but %d children", depth, len(node.keys), len(node.children))
+	}
+	for i, child := range node.children {
+		childLower, childUpper := lower, upper
+		if i > 0 {
+			childLower = &node.keys[i-1]
+		}
+		if i < len(node.keys) {
+			childUpper = &node.keys[i]
+		}
+		if err := bt.validateNode(child, childLower, childUpper, depth+1, leafDepth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (bt *BTree[K, V]) Height() int {
+	if len(bt.root.keys) == 0 {
+		return 0
+	}
+	
+	height := 1
+	for node := bt.root; !node.leaf; node = node.children[0] {
+		height++
+	}
+	return height
 }
 
-func (bt *BTree) isFull(node *BTreeNode) bool {
+func (bt *BTree[K, V]) Count() int {
+	return bt.count
+}
+
+func (bt *BTree[K, V]) isFull(node *BTreeNode[K, V]) bool {
 	return len(node.keys) == 2*bt.degree-1
 }
 
-func (bt *BTree) insertNonFull(node *BTreeNode, key int, value string) {
+func (bt *BTree[K, V]) insertNonFull(node *BTreeNode[K, V], key K, value V) {
 	i := len(node.keys) - 1
 	
 	if node.leaf {
-		node.keys = append(node.keys, 0)
-		node.values = append(node.values, "")
+		var zeroKey K
+		var zeroValue V
+		node.keys = append(node.keys, zeroKey)
+		node.values = append(node.values, zeroValue)
 		
 		for i >= 0 && key < node.keys[i] {
 			node.keys[i+1] = node.keys[i]
@@ -220,25 +804,27 @@ func (bt *BTree) insertNonFull(node *BTreeNode, key int, value string) {
 	}
 }
 
-func (bt *BTree) splitChild(parent *BTreeNode, index int) {
+func (bt *BTree[K, V]) splitChild(parent *BTreeNode[K, V], index int) {
 	fullChild := parent.children[index]
-	newChild := &BTreeNode{leaf: fullChild.leaf}
+	newChild := &BTreeNode[K, V]{leaf: fullChild.leaf}
 	
 	mid := bt.degree - 1
 	
-	newChild.keys = make([]int, len(fullChild.keys[mid+1:]))
+	newChild.keys = make([]K, len(fullChild.keys[mid+1:]))
 	copy(newChild.keys, fullChild.keys[mid+1:])
-	newChild.values = make([]string, len(fullChild.values[mid+1:]))
+	newChild.values = make([]V, len(fullChild.values[mid+1:]))
 	copy(newChild.values, fullChild.values[mid+1:])
 	
 	if !fullChild.leaf {
-		newChild.children = make([]*BTreeNode, len(fullChild.children[mid+1:]))
+		newChild.children = make([]*BTreeNode[K, V], len(fullChild.children[mid+1:]))
 		copy(newChild.children, fullChild.children[mid+1:])
 		fullChild.children = fullChild.children[:mid+1]
 	}
 	
-	parent.keys = append(parent.keys, 0)
-	parent.values = append(parent.values, "")
+	var zeroKey K
+	var zeroValue V
+	parent.keys = append(parent.keys, zeroKey)
+	parent.values = append(parent.values, zeroValue)
 	parent.children = append(parent.children, nil)
 	
 	for i := len(parent.keys) - 1; i > index; i-- {
@@ -255,42 +841,408 @@ func (bt *BTree) splitChild(parent *BTreeNode, index int) {
 	fullChild.values = fullChild.values[:mid]
 }
 
-type DecisionNode struct {
-	feature   string
-	threshold float64
-	left      *DecisionNode
-	right     *DecisionNode
-	value     string
-	isLeaf    bool
+type BTreeEntry[K cmp.Ordered, V any] struct {
+	Key   K
+	Value V
 }
 
-type DecisionTree struct {
-	root *DecisionNode
+func (bt *BTree[K, V]) InOrder() []K {
+	entries := bt.InOrderKV()
+	keys := make([]K, len(entries))
+	for i, entry := range entries {
+		keys[i] = entry.Key
+	}
+	return keys
 }
 
-func NewDecisionTree() *DecisionTree {
-	return &DecisionTree{}
+func (bt *BTree[K, V]) InOrderKV() []BTreeEntry[K, V] {
+	var entries []BTreeEntry[K, V]
+	bt.Traverse(func(key K, value V) bool {
+		entries = append(entries, BTreeEntry[K, V]{Key: key, Value: value})
+		return true
+	})
+	return entries
 }
 
-func (dt *DecisionTree) BuildTree() {
-	dt.root = &DecisionNode{
-		feature:   "age",
-		threshold: 30.0,
-		left: &DecisionNode{
-			feature:   "income",
-			threshold: 50000.0,
-			left: &DecisionNode{
-				value:  "reject",
-				isLeaf: true,
-			},
-			right: &DecisionNode{
-				value:  "approve",
-				isLeaf: true,
-			},
-		},
-		right: &DecisionNode{
-			feature:   "credit_score",
-			threshold: 700.0,
+func (bt *BTree[K, V]) Traverse(fn func(key K, value V) bool) {
+	bt.traverseNode(bt.root, fn)
+}
+
+func (bt *BTree[K, V]) traverseNode(node *BTreeNode[K, V], fn func(key K, value V) bool) bool {
+	for i := range node.keys {
+		if !node.leaf && !bt.traverseNode(node.children[i], fn) {
+			return false
+		}
+		if !fn(node.keys[i], node.values[i]) {
+			return false
+		}
+	}
+	if !node.leaf {
+		return bt.traverseNode(node.children[len(node.keys)], fn)
+	}
+	return true
+}
+
+func (bt *BTree[K, V]) InOrderDesc() []K {
+	var keys []K
+	bt.TraverseDesc(func(key K, value V) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+func (bt *BTree[K, V]) TraverseDesc(fn func(key K, value V) bool) {
+	bt.traverseNodeDesc(bt.root, fn)
+}
+
+func (bt *BTree[K, V]) traverseNodeDesc(node *BTreeNode[K, V], fn func(key K, value V) bool) bool {
+	if !node.leaf && !bt.traverseNodeDesc(node.children[len(node.keys)], fn) {
+		return false
+	}
+	for i := len(node.keys) - 1; i >= 0; i-- {
+		if !fn(node.keys[i], node.values[i]) {
+			return false
+		}
+		if !node.leaf && !bt.traverseNodeDesc(node.children[i], fn) {
+			return false
+		}
+	}
+	return true
+}
+
+func (bt *BTree[K, V]) Range(lo, hi K) []BTreeEntry[K, V] {
+	var entries []BTreeEntry[K, V]
+	if lo <= hi {
+		bt.rangeNode(bt.root, lo, hi, &entries)
+	}
+	return entries
+}
+
+func (bt *BTree[K, V]) rangeNode(node *BTreeNode[K, V], lo, hi K, entries *[]BTreeEntry[K, V]) {
+	for i, key := range node.keys {
+		if !node.leaf && lo <= key {
+			bt.rangeNode(node.children[i], lo, hi, entries)
+		}
+		if key > hi {
+			return
+		}
+		if key >= lo {
+			*entries = append(*entries, BTreeEntry[K, V]{Key: key, Value: node.values[i]})
+		}
+	}
+	if !node.leaf {
+		bt.rangeNode(node.children[len(node.keys)], lo, hi, entries)
+	}
+}
+
+func (bt *BTree[K, V]) Min() (K, V, bool) {
+	if len(bt.root.keys) == 0 {
+		var zeroKey K
+		var zeroValue V
+		return zeroKey, zeroValue, false
+	}
+	node := leftmostLeaf(bt.root)
+	return node.keys[0], node.values[0], true
+}
+
+func (bt *BTree[K, V]) Max() (K, V, bool) {
+	if len(bt.root.keys) == 0 {
+		var zeroKey K
+		var zeroValue V
+		return zeroKey, zeroValue, false
+	}
+	node := rightmostLeaf(bt.root)
+	last := len(node.keys) - 1
+	return node.keys[last], node.values[last], true
+}
+
+func (bt *BTree[K, V]) Successor(key K) (K, V, bool) {
+	var nextKey K
+	var nextValue V
+	found := false
+	
+	node := bt.root
+	for {
+		i := 0
+		for i < len(node.keys) && node.keys[i] <= key {
+			i++
+		}
+		if i < len(node.keys) {
+			nextKey, nextValue, found = node.keys[i], node.values[i], true
+		}
+		if node.leaf {
+			return nextKey, nextValue, found
+		}
+		node = node.children[i]
+	}
+}
+
+func (bt *BTree[K, V]) Predecessor(key K) (K, V, bool) {
+	var prevKey K
+	var prevValue V
+	found := false
+	
+	node := bt.root
+	for {
+		i := 0
+		for i < len(node.keys) && node.keys[i] < key {
+			i++
+		}
+		if i > 0 {
+			prevKey, prevValue, found = node.keys[i-1], node.values[i-1], true
+		}
+		if node.leaf {
+			return prevKey, prevValue, found
+		}
+		node = node.children[i]
+	}
+}
+
+func leftmostLeaf[K cmp.Ordered, V any](node *BTreeNode[K, V]) *BTreeNode[K, V] {
+	for !node.leaf {
+		node = node.children[0]
+	}
+	return node
+}
+
+func rightmostLeaf[K cmp.Ordered, V any](node *BTreeNode[K, V]) *BTreeNode[K, V] {
+	for !node.leaf {
+		node = node.children[len(node.children)-1]
+	}
+	return node
+}
+
+func (bt *BTree[K, V]) Delete(key K) bool {
+	deleted := bt.deleteFromNode(bt.root, key)
+	if len(bt.root.keys) == 0 && !bt.root.leaf {
+		bt.root = bt.root.children[0]
+	}
+	if deleted {
+		bt.count--
+	}
+	return deleted
+}
+
+func (bt *BTree[K, V]) deleteFromNode(node *BTreeNode[K, V], key K) bool {
+	i := 0
+	for i < len(node.keys) && key > node.keys[i] {
+		i++
+	}
+	
+	if i < len(node.keys) && key == node.keys[i] {
+		if node.leaf {
+			node.keys = append(node.keys[:i], node.keys[i+1:]...)
+			node.values = append(node.values[:i], node.values[i+1:]...)
+			return true
+		}
+		
+		if len(node.children[i].keys) >= bt.degree {
+			predecessor := rightmostLeaf(node.children[i])
+			last := len(predecessor.keys) - 1
+			node.keys[i] = predecessor.keys[last]
+			node.values[i] = predecessor.values[last]
+			return bt.deleteFromNode(node.children[i], node.keys[i])
+		}
+		
+		if len(node.children[i+1].keys) >= bt.degree {
+			successor := leftmostLeaf(node.children[i+1])
+			node.keys[i] = successor.keys[0]
+			node.values[i] = successor.values[0]
+			return bt.deleteFromNode(node.children[i+1], node.keys[i])
+		}
+		
+		bt.mergeChildren(node, i)
+		return bt.deleteFromNode(node.children[i], key)
+	}
+	
+	if node.leaf {
+		return false
+	}
+	
+	if len(node.children[i].keys) < bt.degree {
+		i = bt.fillChild(node, i)
+	}
+	return bt.deleteFromNode(node.children[i], key)
+}
+
+func (bt *BTree[K, V]) fillChild(parent *BTreeNode[K, V], index int) int {
+	if index > 0 && len(parent.children[index-1].keys) >= bt.degree {
+		bt.borrowFromPrev(parent, index)
+		return index
+	}
+	if index < len(parent.children)-1 && len(parent.children[index+1].keys) >= bt.degree {
+		bt.borrowFromNext(parent, index)
+		return index
+	}
+	if index < len(parent.children)-1 {
+		bt.mergeChildren(parent, index)
+		return index
+	}
+	bt.mergeChildren(parent, index-1)
+	return index - 1
+}
+
+func (bt *BTree[K, V]) borrowFromPrev(parent *BTreeNode[K, V], index int) {
+	child := parent.children[index]
+	sibling := parent.children[index-1]
+	last := len(sibling.keys) - 1
+	
+	child.keys = append([]K{parent.keys[index-1]}, child.keys...)
+	child.values = append([]V{parent.values[index-1]}, child.values...)
+	parent.keys[index-1] = sibling.keys[last]
+	parent.values[index-1] = sibling.values[last]
+	
+	if !child.leaf {
+		lastChild := len(sibling.children) - 1
+		child.children = append([]*BTreeNode[K, V]{sibling.children[lastChild]}, child.children...)
+		sibling.children = sibling.children[:lastChild]
+	}
+	
+	sibling.keys = sibling.keys[:last]
+	sibling.values = sibling.values[:last]
+}
+
+func (bt *BTree[K, V]) borrowFromNext(parent *BTreeNode[K, V], index int) {
+	child := parent.children[index]
+	sibling := parent.children[index+1]
+	
+	child.keys = append(child.keys, parent.keys[index])
+	child.values = append(child.values, parent.values[index])
+	parent.keys[index] = sibling.keys[0]
+	parent.values[index] = sibling.values[0]
+	
+	if !child.leaf {
+		child.children = append(child.children, sibling.children[0])
+		sibling.children = sibling.children[1:]
+	}
+	
+	sibling.keys = sibling.keys[1:]
+	sibling.values = sibling.values[1:]
+}
+
+func (bt *BTree[K, V]) mergeChildren(parent *BTreeNode[K, V], index int) {
+	child := parent.children[index]
+	sibling := parent.children[index+1]
+	
+	child.keys = append(append(child.keys, parent.keys[index]), sibling.keys...)
+	child.values = append(append(child.values, parent.values[index]), sibling.values...)
+	if !child.leaf {
+		child.children = append(child.children, sibling.children...)
+	}
+	
+	parent.keys = append(parent.keys[:index], parent.keys[index+1:]...)
+	parent.values = append(parent.values[:index], parent.values[index+1:]...)
+	parent.children = append(parent.children[:index+1], parent.children[index+2:]...)
+}
+
+type btreeNodeSnapshot[K cmp.Ordered, V any] struct {
+	Keys     []K
+	Values   []V
+	Leaf     bool
+	Children []btreeNodeSnapshot[K, V]
+}
+
+type btreeSnapshot[K cmp.Ordered, V any] struct {
+	Degree int
+	Count  int
+	Root   btreeNodeSnapshot[K, V]
+}
+
+func snapshotNode[K cmp.Ordered, V any](node *BTreeNode[K, V]) btreeNodeSnapshot[K, V] {
+	snapshot := btreeNodeSnapshot[K, V]{Keys: node.keys, Values: node.values, Leaf: node.leaf}
+	for _, child := range node.children {
+		snapshot.Children = append(snapshot.Children, snapshotNode(child))
+	}
+	return snapshot
+}
+
+func restoreNode[K cmp.Ordered, V any](snapshot btreeNodeSnapshot[K, V]) *BTreeNode[K, V] {
+	node := &BTreeNode[K, V]{keys: snapshot.Keys, values: snapshot.Values, leaf: snapshot.Leaf}
+	for _, child := range snapshot.Children {
+		node.children = append(node.children, restoreNode(child))
+	}
+	return node
+}
+
+func (bt *BTree[K, V]) Save(w io.Writer) error {
+	snapshot := btreeSnapshot[K, V]{
+		Degree: bt.degree,
+		Count:  bt.count,
+		Root:   snapshotNode(bt.root),
+	}
+	if err := gob.NewEncoder(w).Encode(snapshot); err != nil {
+		return fmt.Errorf("saving B-tree: %w", err)
+	}
+	return nil
+}
+
+func Load[K cmp.Ordered, V any](r io.Reader) (*BTree[K, V], error) {
+	var snapshot btreeSnapshot[K, V]
+	if err := gob.NewDecoder(r).Decode(&snapshot); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("loading B-tree: %w", err)
+	}
+	if snapshot.Degree < 2 {
+		return nil, fmt.Errorf("loading B-tree: corrupted stream: invalid degree %d", snapshot.Degree)
+	}
+	
+	bt := &BTree[K, V]{
+		root:   restoreNode(snapshot.Root),
+		degree: snapshot.Degree,
+	}
+	if err := bt.Validate(); err != nil {
+		return nil, fmt.Errorf("loading B-tree: corrupted stream: %w", err)
+	}
+	bt.count = len(bt.InOrder())
+	if bt.count != snapshot.Count {
+		return nil, fmt.Errorf("loading B-tree: corrupted stream: expected %d keys, found %d", snapshot.Count, bt.count)
+	}
+	return bt, nil
+}
+
+type DecisionNode struct {
+	feature       string
+	threshold     float64
+	left          *DecisionNode
+	right         *DecisionNode
+	categories    map[string]*DecisionNode
+	defaultBranch *DecisionNode
+	value         string
+	counts        map[string]int
+	prediction    float64
+	isLeaf        bool
+}
+
+type DecisionTree struct {
+	root *DecisionNode
+}
+
+func NewDecisionTree() *DecisionTree {
+	return &DecisionTree{}
+}
+
+func (dt *DecisionTree) BuildTree() {
+	dt.root = &DecisionNode{
+		feature:   "age",
+		threshold: 30.0,
+		left: &DecisionNode{
+			feature:   "income",
+			threshold: 50000.0,
+			left: &DecisionNode{
+				value:  "reject",
+				isLeaf: true,
+			},
+			right: &DecisionNode{
+				value:  "approve",
+				isLeaf: true,
+			},
+		},
+		right: &DecisionNode{
+			feature:   "credit_score",
+			threshold: 700.0,
 			left: &DecisionNode{
 				value:  "review",
 				isLeaf: true,
@@ -303,24 +1255,778 @@ func (dt *DecisionTree) BuildTree() {
 	}
 }
 
+type Sample struct {
+	Features   map[string]float64
+	Categories map[string]string
+	Label      string
+}
+
+// Criterion selects the impurity measure used to score candidate splits.
+// Entropy and Gini usually agree on clean, well-separated data; they can
+// pick different thresholds when a split trades purity for balance.
+type Criterion int
+
+const (
+	Entropy Criterion = iota
+	Gini
+)
+
+func (c Criterion) String() string {
+	if c == Gini {
+		return "Gini"
+	}
+	return "Entropy"
+}
+
+type TrainOptions struct {
+	Criterion        Criterion
+	MaxDepth         int
+	MinSamplesSplit  int
+	FeaturesPerSplit int
+	Seed             int64
+	rng              *rand.Rand
+}
+
+func (dt *DecisionTree) Train(samples []Sample, features []string, opts TrainOptions) error {
+	if len(samples) == 0 {
+		return fmt.Errorf("no training samples")
+	}
+	if opts.FeaturesPerSplit > 0 && opts.rng == nil {
+		opts.rng = rand.New(rand.NewSource(opts.Seed))
+	}
+	dt.root = buildDecisionNode(samples, features, opts, 0)
+	return nil
+}
+
+func buildDecisionNode(samples []Sample, features []string, opts TrainOptions, depth int) *DecisionNode {
+	counts := labelCounts(samples)
+	leaf := &DecisionNode{value: majorityLabel(counts), counts: counts, isLeaf: true}
+	if len(counts) == 1 {
+		return leaf
+	}
+	if opts.MaxDepth > 0 && depth >= opts.MaxDepth {
+		return leaf
+	}
+	if opts.MinSamplesSplit > 0 && len(samples) < opts.MinSamplesSplit {
+		return leaf
+	}
+	
+	candidates := features
+	if opts.rng != nil && opts.FeaturesPerSplit > 0 && opts.FeaturesPerSplit < len(features) {
+		candidates = make([]string, opts.FeaturesPerSplit)
+		for i, index := range opts.rng.Perm(len(features))[:opts.FeaturesPerSplit] {
+			candidates[i] = features[index]
+		}
+	}
+	
+	parentScore := impurity(counts, len(samples), opts.Criterion)
+	bestFeature, bestThreshold, bestScore := "", 0.0, parentScore
+	categorical := false
+	for _, feature := range candidates {
+		if isCategoricalFeature(samples, feature) {
+			if score, ok := categoricalSplitScore(samples, feature, opts.Criterion); ok && score < bestScore-1e-12 {
+				bestFeature, bestScore, categorical = feature, score, true
+			}
+			continue
+		}
+		if threshold, score, ok := bestNumericSplit(samples, feature, opts.Criterion); ok && score < bestScore-1e-12 {
+			bestFeature, bestThreshold, bestScore, categorical = feature, threshold, score, false
+		}
+	}
+	if bestFeature == "" {
+		return leaf
+	}
+	
+	if categorical {
+		groups := make(map[string][]Sample)
+		for _, sample := range samples {
+			value := sample.Categories[bestFeature]
+			groups[value] = append(groups[value], sample)
+		}
+		
+		remaining := make([]string, 0, len(features)-1)
+		for _, feature := range features {
+			if feature != bestFeature {
+				remaining = append(remaining, feature)
+			}
+		}
+		
+		node := &DecisionNode{
+			feature:       bestFeature,
+			categories:    make(map[string]*DecisionNode),
+			defaultBranch: leaf,
+			value:         leaf.value,
+			counts:        counts,
+		}
+		for value, group := range groups {
+			node.categories[value] = buildDecisionNode(group, remaining, opts, depth+1)
+		}
+		return node
+	}
+	
+	var left, right []Sample
+	for _, sample := range samples {
+		if sample.Features[bestFeature] <= bestThreshold {
+			left = append(left, sample)
+		} else {
+			right = append(right, sample)
+		}
+	}
+	return &DecisionNode{
+		feature:   bestFeature,
+		threshold: bestThreshold,
+		left:      buildDecisionNode(left, features, opts, depth+1),
+		right:     buildDecisionNode(right, features, opts, depth+1),
+		value:     leaf.value,
+		counts:    counts,
+	}
+}
+
+type RegressionSample struct {
+	Features map[string]float64
+	Target   float64
+}
+
+func (dt *DecisionTree) TrainRegression(samples []RegressionSample, features []string, opts TrainOptions) error {
+	if len(samples) == 0 {
+		return fmt.Errorf("no training samples")
+	}
+	dt.root = buildRegressionNode(samples, features, opts, 0)
+	return nil
+}
+
+func buildRegressionNode(samples []RegressionSample, features []string, opts TrainOptions, depth int) *DecisionNode {
+	sum, sumSquares := 0.0, 0.0
+	for _, sample := range samples {
+		sum += sample.Target
+		sumSquares += sample.Target * sample.Target
+	}
+	n := float64(len(samples))
+	mean := sum / n
+	leaf := &DecisionNode{value: fmt.Sprintf("%.4g", mean), prediction: mean, isLeaf: true}
+	
+	bestFeature, bestThreshold := "", 0.0
+	bestError := sumSquares - sum*sum/n
+	if bestError <= 1e-12 {
+		return leaf
+	}
+	if opts.MaxDepth > 0 && depth >= opts.MaxDepth {
+		return leaf
+	}
+	if opts.MinSamplesSplit > 0 && len(samples) < opts.MinSamplesSplit {
+		return leaf
+	}
+	
+	for _, feature := range features {
+		sorted := append([]RegressionSample(nil), samples...)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].Features[feature] < sorted[j].Features[feature]
+		})
+		
+		leftSum, leftSquares := 0.0, 0.0
+		for i := 0; i < len(sorted)-1; i++ {
+			leftSum += sorted[i].Target
+			leftSquares += sorted[i].Target * sorted[i].Target
+			current, next := sorted[i].Features[feature], sorted[i+1].Features[feature]
+			if current == next {
+				continue
+			}
+			
+			leftN, rightN := float64(i+1), n-float64(i+1)
+			rightSum, rightSquares := sum-leftSum, sumSquares-leftSquares
+			splitError := (leftSquares - leftSum*leftSum/leftN) + (rightSquares - rightSum*rightSum/rightN)
+			if splitError < bestError-1e-12 {
+				bestFeature, bestThreshold, bestError = feature, (current+next)/2, splitError
+			}
+		}
+	}
+	if bestFeature == "" {
+		return leaf
+	}
+	
+	var left, right []RegressionSample
+	for _, sample := range samples {
+		if sample.Features[bestFeature] <= bestThreshold {
+			left = append(left, sample)
+		} else {
+			right = append(right, sample)
+		}
+	}
+	return &DecisionNode{
+		feature:    bestFeature,
+		threshold:  bestThreshold,
+		left:       buildRegressionNode(left, features, opts, depth+1),
+		right:      buildRegressionNode(right, features, opts, depth+1),
+		value:      leaf.value,
+		prediction: mean,
+	}
+}
+
+func isCategoricalFeature(samples []Sample, feature string) bool {
+	for _, sample := range samples {
+		if _, ok := sample.Categories[feature]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func bestNumericSplit(samples []Sample, feature string, criterion Criterion) (float64, float64, bool) {
+	sorted := append([]Sample(nil), samples...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Features[feature] < sorted[j].Features[feature]
+	})
+	
+	bestThreshold, bestScore, found := 0.0, math.Inf(1), false
+	leftCounts := make(map[string]int)
+	rightCounts := labelCounts(sorted)
+	for i := 0; i < len(sorted)-1; i++ {
+		leftCounts[sorted[i].Label]++
+		rightCounts[sorted[i].Label]--
+		current, next := sorted[i].Features[feature], sorted[i+1].Features[feature]
+		if current == next {
+			continue
+		}
+		
+		leftSize, rightSize := i+1, len(sorted)-i-1
+		score := (float64(leftSize)*impurity(leftCounts, leftSize, criterion) +
+			float64(rightSize)*impurity(rightCounts, rightSize, criterion)) / float64(len(sorted))
+		if score < bestScore-1e-12 {
+			bestThreshold, bestScore, found = (current+next)/2, score, true
+		}
+	}
+	return bestThreshold, bestScore, found
+}
+
+func categoricalSplitScore(samples []Sample, feature string, criterion Criterion) (float64, bool) {
+	groups := make(map[string]map[string]int)
+	sizes := make(map[string]int)
+	for _, sample := range samples {
+		value := sample.Categories[feature]
+		if groups[value] == nil {
+			groups[value] = make(map[string]int)
+		}
+		groups[value][sample.Label]++
+		sizes[value]++
+	}
+	if len(groups) < 2 {
+		return 0, false
+	}
+	
+	score := 0.0
+	for value, counts := range groups {
+		score += float64(sizes[value]) * impurity(counts, sizes[value], criterion)
+	}
+	return score / float64(len(samples)), true
+}
+
+type EvalResult struct {
+	Accuracy  float64
+	Confusion map[string]map[string]int
+}
+
+func (dt *DecisionTree) Evaluate(samples []Sample) EvalResult {
+	result := EvalResult{Confusion: make(map[string]map[string]int)}
+	if len(samples) == 0 {
+		return result
+	}
+	
+	correct := 0
+	for _, sample := range samples {
+		predicted := dt.PredictSample(sample)
+		if result.Confusion[sample.Label] == nil {
+			result.Confusion[sample.Label] = make(map[string]int)
+		}
+		result.Confusion[sample.Label][predicted]++
+		if predicted == sample.Label {
+			correct++
+		}
+	}
+	result.Accuracy = float64(correct) / float64(len(samples))
+	return result
+}
+
+func CrossValidate(samples []Sample, features []string, k int, seed int64) ([]float64, error) {
+	if k < 2 || k > len(samples) {
+		return nil, fmt.Errorf("fold count must be between 2 and %d, got %d", len(samples), k)
+	}
+	
+	rng := rand.New(rand.NewSource(seed))
+	shuffled := make([]Sample, len(samples))
+	for i, index := range rng.Perm(len(samples)) {
+		shuffled[i] = samples[index]
+	}
+	
+	accuracies := make([]float64, 0, k)
+	for fold := 0; fold < k; fold++ {
+		start := fold * len(shuffled) / k
+		end := (fold + 1) * len(shuffled) / k
+		
+		training := make([]Sample, 0, len(shuffled)-(end-start))
+		training = append(training, shuffled[:start]...)
+		training = append(training, shuffled[end:]...)
+		
+		tree := NewDecisionTree()
+		if err := tree.Train(training, features, TrainOptions{}); err != nil {
+			return nil, fmt.Errorf("fold %d: %w", fold+1, err)
+		}
+		accuracies = append(accuracies, tree.Evaluate(shuffled[start:end]).Accuracy)
+	}
+	return accuracies, nil
+}
+
+type RandomForest struct {
+	trees []*DecisionTree
+	rng   *rand.Rand
+}
+
+func NewRandomForest(seed int64) *RandomForest {
+	return &RandomForest{rng: rand.New(rand.NewSource(seed))}
+}
+
+func (rf *RandomForest) TrainForest(samples []Sample, features []string, numTrees, featuresPerSplit int) error {
+	if len(samples) == 0 {
+		return fmt.Errorf("no training samples")
+	}
+	if numTrees <= 0 {
+		return fmt.Errorf("number of trees must be positive, got %d", numTrees)
+	}
+	
+	rf.trees = make([]*DecisionTree, 0, numTrees)
+	for i := 0; i < numTrees; i++ {
+		bootstrap := make([]Sample, len(samples))
+		for j := range bootstrap {
+			bootstrap[j] = samples[rf.rng.Intn(len(samples))]
+		}
+		
+		tree := NewDecisionTree()
+		opts := TrainOptions{FeaturesPerSplit: featuresPerSplit, rng: rf.rng}
+		if err := tree.Train(bootstrap, features, opts); err != nil {
+			return err
+		}
+		rf.trees = append(rf.trees, tree)
+	}
+	return nil
+}
+
+func (rf *RandomForest) Predict(features map[string]float64) string {
+	return rf.PredictSample(Sample{Features: features})
+}
+
+func (rf *RandomForest) PredictSample(sample Sample) string {
+	votes := make(map[string]int)
+	for _, tree := range rf.trees {
+		votes[tree.PredictSample(sample)]++
+	}
+	return majorityLabel(votes)
+}
+
+func labelCounts(samples []Sample) map[string]int {
+	counts := make(map[string]int)
+	for _, sample := range samples {
+		counts[sample.Label]++
+	}
+	return counts
+}
+
+func majorityLabel(counts map[string]int) string {
+	best, bestCount := "", -1
+	for label, count := range counts {
+		if count > bestCount || (count == bestCount && label < best) {
+			best, bestCount = label, count
+		}
+	}
+	return best
+}
+
+func impurity(counts map[string]int, total int, criterion Criterion) float64 {
+	if total == 0 {
+		return 0
+	}
+	
+	result := 0.0
+	if criterion == Gini {
+		result = 1
+	}
+	for _, count := range counts {
+		if count == 0 {
+			continue
+		}
+		p := float64(count) / float64(total)
+		if criterion == Gini {
+			result -= p * p
+		} else {
+			result -= p * math.Log2(p)
+		}
+	}
+	return result
+}
+
 func (dt *DecisionTree) Predict(age float64, income float64, creditScore float64) string {
-	return dt.traverse(dt.root, map[string]float64{
+	return dt.PredictMap(map[string]float64{
 		"age":          age,
 		"income":       income,
 		"credit_score": creditScore,
 	})
 }
 
+// PredictMap routes a sample with arbitrary named features. A feature
+// missing from the map follows the "<= threshold" (left) branch.
+func (dt *DecisionTree) PredictMap(features map[string]float64) string {
+	return dt.traverse(dt.root, features)
+}
+
+type decisionNodeJSON struct {
+	Feature       string                       `json:"feature,omitempty"`
+	Threshold     float64                      `json:"threshold,omitempty"`
+	Left          *decisionNodeJSON            `json:"left,omitempty"`
+	Right         *decisionNodeJSON            `json:"right,omitempty"`
+	Categories    map[string]*decisionNodeJSON `json:"categories,omitempty"`
+	DefaultBranch *decisionNodeJSON            `json:"default,omitempty"`
+	Value         string                       `json:"value,omitempty"`
+	Counts        map[string]int               `json:"counts,omitempty"`
+	Prediction    float64                      `json:"prediction,omitempty"`
+	IsLeaf        bool                         `json:"isLeaf,omitempty"`
+}
+
+func (node *DecisionNode) toJSON() *decisionNodeJSON {
+	if node == nil {
+		return nil
+	}
+	
+	snapshot := &decisionNodeJSON{
+		Feature:       node.feature,
+		Threshold:     node.threshold,
+		Left:          node.left.toJSON(),
+		Right:         node.right.toJSON(),
+		DefaultBranch: node.defaultBranch.toJSON(),
+		Value:         node.value,
+		Counts:        node.counts,
+		Prediction:    node.prediction,
+		IsLeaf:        node.isLeaf,
+	}
+	if node.categories != nil {
+		snapshot.Categories = make(map[string]*decisionNodeJSON, len(node.categories))
+		for value, child := range node.categories {
+			snapshot.Categories[value] = child.toJSON()
+		}
+	}
+	return snapshot
+}
+
+func (snapshot *decisionNodeJSON) toNode() (*DecisionNode, error) {
+	if snapshot == nil {
+		return nil, fmt.Errorf("missing node")
+	}
+	
+	node := &DecisionNode{
+		feature:    snapshot.Feature,
+		threshold:  snapshot.Threshold,
+		value:      snapshot.Value,
+		counts:     snapshot.Counts,
+		prediction: snapshot.Prediction,
+		isLeaf:     snapshot.IsLeaf,
+	}
+	if node.isLeaf {
+		if snapshot.Left != nil || snapshot.Right != nil || snapshot.Categories != nil || snapshot.DefaultBranch != nil {
+			return nil, fmt.Errorf("leaf %q has children", snapshot.Value)
+		}
+		return node, nil
+	}
+	if node.feature == "" {
+		return nil, fmt.Errorf("internal node has no feature")
+	}
+	
+	var err error
+	if snapshot.Categories != nil {
+		if snapshot.Left != nil || snapshot.Right != nil {
+			return nil, fmt.Errorf("categorical node %q has threshold branches", node.feature)
+		}
+		if node.defaultBranch, err = snapshot.DefaultBranch.toNode(); err != nil {
+			return nil, fmt.Errorf("default branch of %q: %w", node.feature, err)
+		}
+		node.categories = make(map[string]*DecisionNode, len(snapshot.Categories))
+		for value, childSnapshot := range snapshot.Categories {
+			if node.categories[value], err = childSnapshot.toNode(); err != nil {
+				return nil, fmt.Errorf("category %q of %q: %w", value, node.feature, err)
+			}
+		}
+		return node, nil
+	}
+	
+	if node.left, err = snapshot.Left.toNode(); err != nil {
+		return nil, fmt.Errorf("left branch of %q: %w", node.feature, err)
+	}
+	if node.right, err = snapshot.Right.toNode(); err != nil {
+		return nil, fmt.Errorf("right branch of %q: %w", node.feature, err)
+	}
+	return node, nil
+}
+
+func (dt *DecisionTree) Save(w io.Writer) error {
+	if dt.root == nil {
+		return fmt.Errorf("decision tree has not been built")
+	}
+	return json.NewEncoder(w).Encode(dt.root.toJSON())
+}
+
+func LoadDecisionTree(r io.Reader) (*DecisionTree, error) {
+	var snapshot *decisionNodeJSON
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("invalid decision tree model: %w", err)
+	}
+	
+	root, err := snapshot.toNode()
+	if err != nil {
+		return nil, fmt.Errorf("invalid decision tree model: %w", err)
+	}
+	return &DecisionTree{root: root}, nil
+}
+
+var ErrMissingFeature = errors.New("missing feature")
+
+func (dt *DecisionTree) PredictStrict(sample Sample) (string, error) {
+	if dt.root == nil {
+		return "", fmt.Errorf("decision tree has not been built")
+	}
+	
+	node := dt.root
+	for !node.isLeaf {
+		ok := false
+		if node.categories != nil {
+			_, ok = sample.Categories[node.feature]
+		} else {
+			_, ok = sample.Features[node.feature]
+		}
+		if !ok {
+			return "", fmt.Errorf("%w: %s", ErrMissingFeature, node.feature)
+		}
+		node = node.childFor(sample.Features, sample.Categories)
+	}
+	return node.value, nil
+}
+
+func (dt *DecisionTree) PredictValue(features map[string]float64) (float64, bool) {
+	if dt.root == nil {
+		return 0, false
+	}
+	
+	node := dt.root
+	for !node.isLeaf {
+		node = node.childFor(features, nil)
+	}
+	return node.prediction, true
+}
+
+func (dt *DecisionTree) PredictProba(features map[string]float64) map[string]float64 {
+	if dt.root == nil {
+		return nil
+	}
+	
+	node := dt.root
+	for !node.isLeaf {
+		node = node.childFor(features, nil)
+	}
+	
+	total := 0
+	for _, count := range node.counts {
+		total += count
+	}
+	if total == 0 {
+		return map[string]float64{node.value: 1}
+	}
+	
+	probabilities := make(map[string]float64, len(node.counts))
+	for label, count := range node.counts {
+		if count > 0 {
+			probabilities[label] = float64(count) / float64(total)
+		}
+	}
+	return probabilities
+}
+
+func (dt *DecisionTree) PredictSample(sample Sample) string {
+	return dt.traverseMixed(dt.root, sample.Features, sample.Categories)
+}
+
 func (dt *DecisionTree) traverse(node *DecisionNode, features map[string]float64) string {
+	return dt.traverseMixed(node, features, nil)
+}
+
+func (dt *DecisionTree) traverseMixed(node *DecisionNode, features map[string]float64, categories map[string]string) string {
+	return predictFrom(node, Sample{Features: features, Categories: categories})
+}
+
+func (node *DecisionNode) childFor(features map[string]float64, categories map[string]string) *DecisionNode {
+	if node.categories != nil {
+		if child, ok := node.categories[categories[node.feature]]; ok {
+			return child
+		}
+		return node.defaultBranch
+	}
+	
+	featureValue, ok := features[node.feature]
+	if !ok || featureValue <= node.threshold {
+		return node.left
+	}
+	return node.right
+}
+
+func (node *DecisionNode) children() []*DecisionNode {
+	if node.isLeaf {
+		return nil
+	}
+	if node.categories == nil {
+		return []*DecisionNode{node.left, node.right}
+	}
+	
+	children := []*DecisionNode{node.defaultBranch}
+	values := make([]string, 0, len(node.categories))
+	for value := range node.categories {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	for _, value := range values {
+		children = append(children, node.categories[value])
+	}
+	return children
+}
+
+func (node *DecisionNode) splitLabel() string {
 	if node.isLeaf {
 		return node.value
 	}
+	if node.categories != nil {
+		return node.feature + " = ?"
+	}
+	return fmt.Sprintf("%s <= %g", node.feature, node.threshold)
+}
+
+func (node *DecisionNode) branches() ([]string, []*DecisionNode) {
+	if node.isLeaf {
+		return nil, nil
+	}
+	if node.categories == nil {
+		return []string{"yes", "no"}, []*DecisionNode{node.left, node.right}
+	}
+	
+	labels := []string{"default"}
+	values := make([]string, 0, len(node.categories))
+	for value := range node.categories {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	return append(labels, values...), node.children()
+}
+
+func (dt *DecisionTree) String() string {
+	if dt.root == nil {
+		return "(empty tree)\n"
+	}
+	
+	var sb strings.Builder
+	var write func(node *DecisionNode, indent string)
+	write = func(node *DecisionNode, indent string) {
+		labels, children := node.branches()
+		for i, child := range children {
+			fmt.Fprintf(&sb, "%s%s: %s\n", indent, labels[i], child.splitLabel())
+			write(child, indent+"  ")
+		}
+	}
+	sb.WriteString(dt.root.splitLabel() + "\n")
+	write(dt.root, "  ")
+	return sb.String()
+}
+
+func (dt *DecisionTree) ToDOT() string {
+	var sb strings.Builder
+	sb.WriteString("digraph DecisionTree {\n")
+	
+	nextID := 0
+	var write func(node *DecisionNode) int
+	write = func(node *DecisionNode) int {
+		id := nextID
+		nextID++
+		shape := "box"
+		if node.isLeaf {
+			shape = "ellipse"
+		}
+		fmt.Fprintf(&sb, "  node%d [label=%q, shape=%s];\n", id, node.splitLabel(), shape)
+		
+		labels, children := node.branches()
+		for i, child := range children {
+			childID := write(child)
+			fmt.Fprintf(&sb, "  node%d -> node%d [label=%q];\n", id, childID, labels[i])
+		}
+		return id
+	}
+	if dt.root != nil {
+		write(dt.root)
+	}
+	
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func (dt *DecisionTree) NodeCount() int {
+	if dt.root == nil {
+		return 0
+	}
+	
+	count := 0
+	stack := []*DecisionNode{dt.root}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = append(stack[:len(stack)-1], node.children()...)
+		count++
+	}
+	return count
+}
+
+func (dt *DecisionTree) Prune(validation []Sample) {
+	if dt.root != nil {
+		pruneNode(dt.root, validation)
+	}
+}
+
+func pruneNode(node *DecisionNode, samples []Sample) {
+	if node.isLeaf || len(samples) == 0 {
+		return
+	}
+	
+	routed := make(map[*DecisionNode][]Sample)
+	for _, sample := range samples {
+		child := node.childFor(sample.Features, sample.Categories)
+		routed[child] = append(routed[child], sample)
+	}
+	for _, child := range node.children() {
+		pruneNode(child, routed[child])
+	}
+	
+	label := node.value
+	if label == "" {
+		label = majorityLabel(labelCounts(samples))
+	}
 	
-	featureValue := features[node.feature]
-	if featureValue <= node.threshold {
-		return dt.traverse(node.left, features)
+	subtreeErrors, leafErrors := 0, 0
+	for _, sample := range samples {
+		if predictFrom(node, sample) != sample.Label {
+			subtreeErrors++
+		}
+		if label != sample.Label {
+			leafErrors++
+		}
+	}
+	if leafErrors <= subtreeErrors {
+		*node = DecisionNode{value: label, counts: node.counts, isLeaf: true}
+	}
+}
+
+func predictFrom(node *DecisionNode, sample Sample) string {
+	for !node.isLeaf {
+		node = node.childFor(sample.Features, sample.Categories)
 	}
-	return dt.traverse(node.right, features)
+	return node.value
 }
 
 func main() {
@@ -332,18 +2038,134 @@ func main() {
 	fs.CreateFile("/home/user/documents/photo.jpg", 2048576)
 	fs.CreateDir("/var/log")
 	fs.CreateFile("/var/log/system.log", 4096)
+	fs.CreateFile("topfile.txt", 100)
 	
 	fmt.Println("File system structure:")
 	fs.PrintTree(nil, "")
 	
+	fmt.Println("\nTop two levels only:")
+	fs.PrintTreeDepth(nil, 2)
+	
 	fmt.Println("\nFiles in /home/user/documents:")
-	files := fs.List("/home/user/documents")
+	files, err := fs.List("/home/user/documents")
+	if err != nil {
+		fmt.Printf("  Error: %v\n", err)
+	}
 	for _, file := range files {
 		fmt.Printf("  %s\n", file)
 	}
+	if biggest, err := fs.ListSorted("/home/user/documents", SortKey{Field: SortBySize, Descending: true}); err == nil {
+		fmt.Printf("  Biggest first: %v\n", biggest)
+	}
+	if _, err := fs.List("/home/guest"); err != nil {
+		fmt.Printf("  Listing /home/guest: %v\n", err)
+	}
+	
+	fmt.Println("\nFile details:")
+	for _, path := range []string{"/home/user/documents", "/var/log/system.log", "/etc/hosts"} {
+		info, err := fs.Stat(path)
+		if err != nil {
+			fmt.Printf("  Stat failed: %v\n", err)
+			continue
+		}
+		fmt.Printf("  %s: dir=%t size=%d children=%d modified=%s\n",
+			info.Name, info.IsDir, info.Size, info.Children, info.Modified.Format("15:04:05"))
+	}
+	
+	fmt.Println("\nWriting and reading file content:")
+	if err := fs.WriteFile("/home/user/notes.txt", []byte("Buy milk")); err != nil {
+		fmt.Printf("  Write failed: %v\n", err)
+	}
+	fs.WriteFile("/home/user/notes.txt", []byte("Buy milk and eggs"))
+	if data, err := fs.ReadFile("/home/user/notes.txt"); err == nil {
+		fmt.Printf("  notes.txt: %q\n", data)
+	}
+	if _, err := fs.ReadFile("/home/user/documents"); err != nil {
+		fmt.Printf("  Read failed: %v\n", err)
+	}
+	
+	fmt.Println("\nResolving . and .. in paths:")
+	for _, path := range []string{"/home/user/../user/documents", "/home/./user/", "/var/log/../../home", "/home/../.."} {
+		normalized, _ := normalizePath(path)
+		if entries, err := fs.List(path); err != nil {
+			fmt.Printf("  %-30s error: %v\n", path, err)
+		} else {
+			fmt.Printf("  %-30s -> %s %v\n", path, normalized, entries)
+		}
+	}
+	
+	fmt.Println("\nDisk usage:")
+	for _, path := range []string{"/home/user/documents", "/var", "/"} {
+		if usage, err := fs.DiskUsage(path); err == nil {
+			fmt.Printf("  %-22s %d bytes\n", path, usage)
+		}
+	}
+	
+	if files, dirs, err := fs.CountNodes("/home"); err == nil {
+		fmt.Printf("  /home contains %d files in %d folders\n", files, dirs)
+	}
+	
+	fmt.Println("\nLargest files:")
+	for _, file := range fs.LargestFiles(3) {
+		fmt.Printf("  %-32s %d bytes\n", file.Path, file.Size)
+	}
+	
+	fmt.Println("\nFinding files by pattern:")
+	fmt.Printf("  *.log: %v\n", fs.Find("*.log"))
+	fmt.Printf("  photo.*: %v\n", fs.Find("photo.*"))
+	if _, err := fs.FindErr("[a-"); err != nil {
+		fmt.Printf("  Error: %v\n", err)
+	}
+	
+	fmt.Println("\nWalking /home, skipping documents:")
+	fs.Walk("/home", func(fullPath string, node *FileNode) error {
+		if node.isDir && node.name == "documents" {
+			return SkipDir
+		}
+		fmt.Printf("  %s\n", fullPath)
+		return nil
+	})
+	
+	fmt.Println("\nExporting and re-importing as JSON:")
+	if snapshot, err := fs.Export(); err != nil {
+		fmt.Printf("  Export failed: %v\n", err)
+	} else if restored, err := Import(snapshot); err != nil {
+		fmt.Printf("  Import failed: %v\n", err)
+	} else {
+		fmt.Printf("  Snapshot size: %d bytes\n", len(snapshot))
+		entries, _ := restored.List("/home/user/documents")
+		fmt.Printf("  Restored /home/user/documents: %v\n", entries)
+	}
+	
+	fmt.Println("\nDeleting temporary files and directories:")
+	fs.CreateFile("/tmp/session.lock", 0)
+	fs.CreateFile("/tmp/cache/thumbnails.db", 8192)
+	entries, _ := fs.List("/tmp")
+	fmt.Printf("  /tmp before: %v\n", entries)
+	for _, path := range []string{"/tmp/session.lock", "/tmp/cache", "/tmp/missing", "/"} {
+		if err := fs.Delete(path); err != nil {
+			fmt.Printf("  Delete %s failed: %v\n", path, err)
+		}
+	}
+	entries, _ = fs.List("/tmp")
+	fmt.Printf("  /tmp after: %v\n", entries)
+	
+	fmt.Println("\nBacking up /home/user to /backup/user:")
+	if err := fs.Copy("/home/user", "/backup/user"); err != nil {
+		fmt.Printf("  Copy failed: %v\n", err)
+	}
+	if err := fs.Copy("/var/log", "/backup/user"); err != nil {
+		fmt.Printf("  Copy failed: %v\n", err)
+	}
+	fs.PrintTree(fs.root.children["backup"], "  ")
+	fs.Delete("/home/user")
+	entries, _ = fs.List("/home")
+	fmt.Printf("  /home after deleting the original: %v\n", entries)
+	entries, _ = fs.List("/backup/user")
+	fmt.Printf("  /backup/user still holds: %v\n", entries)
 
 	fmt.Println("\n=== Database B-Tree Example ===")
-	btree := NewBTree(3)
+	btree := NewBTree[int, string](3)
 	btree.Insert(1, "Record 1")
 	btree.Insert(3, "Record 3")
 	btree.Insert(7, "Record 7")
@@ -366,6 +2188,115 @@ func main() {
 	if _, found := btree.Search(99); !found {
 		fmt.Println("Key 99 not found (as expected)")
 	}
+	if err := btree.InsertUnique(15, "Duplicate 15"); err != nil {
+		fmt.Printf("InsertUnique rejected: %v\n", err)
+	}
+	
+	fmt.Printf("Keys in order: %v\n", btree.InOrder())
+	fmt.Printf("Height: %d, Count: %d\n", btree.Height(), btree.Count())
+	if minKey, minValue, ok := btree.Min(); ok {
+		maxKey, maxValue, _ := btree.Max()
+		fmt.Printf("Min: %d (%s), Max: %d (%s)\n", minKey, minValue, maxKey, maxValue)
+	}
+	
+	for _, key := range []int{15, 12} {
+		prevKey, _, _ := btree.Predecessor(key)
+		nextKey, _, _ := btree.Successor(key)
+		fmt.Printf("Neighbors of %d: predecessor %d, successor %d\n", key, prevKey, nextKey)
+	}
+	if _, _, ok := btree.Successor(24); !ok {
+		fmt.Println("No successor for 24 (largest key)")
+	}
+	
+	btree.Traverse(func(key int, value string) bool {
+		if key > 12 {
+			fmt.Printf("First key above 12: %d (%s)\n", key, value)
+			return false
+		}
+		return true
+	})
+	
+	fmt.Printf("Keys descending: %v\n", btree.InOrderDesc())
+	fmt.Print("Top 3 keys:")
+	remaining := 3
+	btree.TraverseDesc(func(key int, value string) bool {
+		fmt.Printf(" %d", key)
+		remaining--
+		return remaining > 0
+	})
+	fmt.Println()
+	
+	fmt.Println("Range(10, 16):")
+	for _, entry := range btree.Range(10, 16) {
+		fmt.Printf("  %d: %s\n", entry.Key, entry.Value)
+	}
+	
+	fmt.Println("Deleting keys 13, 10, 3 and 99:")
+	for _, key := range []int{13, 10, 3, 99} {
+		fmt.Printf("  Delete(%d) -> %t\n", key, btree.Delete(key))
+	}
+	for _, key := range []int{1, 3, 7, 10, 11, 13, 14, 24} {
+		_, found := btree.Search(key)
+		fmt.Printf("  Search(%d) found: %t\n", key, found)
+	}
+	fmt.Printf("Keys in order: %v\n", btree.InOrder())
+	if err := btree.Validate(); err != nil {
+		fmt.Printf("Invalid B-tree: %v\n", err)
+	} else {
+		fmt.Println("B-tree invariants hold after deletes")
+	}
+
+	var saved bytes.Buffer
+	if err := btree.Save(&saved); err != nil {
+		fmt.Printf("Save failed: %v\n", err)
+	}
+	truncated := bytes.NewReader(saved.Bytes()[:saved.Len()/2])
+	if restored, err := Load[int, string](&saved); err != nil {
+		fmt.Printf("Load failed: %v\n", err)
+	} else {
+		fmt.Printf("Reloaded from stream: %v\n", restored.InOrder())
+	}
+	if _, err := Load[int, string](truncated); err != nil {
+		fmt.Printf("Truncated stream rejected: %v\n", err)
+	}
+
+	large := NewBTree[int, string](3)
+	for key := 1; key <= 1000; key++ {
+		large.Insert(key, fmt.Sprintf("Record %d", key))
+	}
+	fmt.Printf("After 1000 sequential inserts: height %d, count %d\n", large.Height(), large.Count())
+
+	sorted := make([]BTreeEntry[int, string], 100000)
+	for i := range sorted {
+		sorted[i] = BTreeEntry[int, string]{Key: i, Value: fmt.Sprintf("Record %d", i)}
+	}
+	start := time.Now()
+	inserted := NewBTree[int, string](3)
+	for _, entry := range sorted {
+		inserted.Insert(entry.Key, entry.Value)
+	}
+	insertTime := time.Since(start)
+	start = time.Now()
+	bulk := NewBTree[int, string](3)
+	if err := bulk.BulkLoad(sorted); err != nil {
+		fmt.Printf("Bulk load failed: %v\n", err)
+	}
+	bulkTime := time.Since(start)
+	fmt.Printf("100k keys: Insert %v (height %d), BulkLoad %v (height %d)\n",
+		insertTime, inserted.Height(), bulkTime, bulk.Height())
+	if err := bulk.BulkLoad([]BTreeEntry[int, string]{{Key: 2}, {Key: 1}}); err != nil {
+		fmt.Printf("Bulk load rejected: %v\n", err)
+	}
+	
+	fmt.Println("String-keyed index:")
+	emails := NewBTree[string, int](2)
+	for id, email := range []string{"dave@example.com", "alice@example.com", "carol@example.com", "bob@example.com"} {
+		emails.Insert(email, id+1)
+	}
+	fmt.Printf("  Emails in order: %v\n", emails.InOrder())
+	if id, found := emails.Search("carol@example.com"); found {
+		fmt.Printf("  carol@example.com -> user %d\n", id)
+	}
 
 	fmt.Println("\n=== Decision Tree Example ===")
 	dt := NewDecisionTree()
@@ -385,4 +2316,210 @@ func main() {
 		result := dt.Predict(tc.age, tc.income, tc.creditScore)
 		fmt.Printf("%s -> %s\n", tc.description, result)
 	}
+	
+	fmt.Printf("\nTree structure:\n%s", dt)
+	fmt.Printf("Graphviz:\n%s\n", dt.ToDOT())
+	
+	var model bytes.Buffer
+	if err := dt.Save(&model); err != nil {
+		fmt.Printf("Save failed: %v\n", err)
+	} else if loaded, err := LoadDecisionTree(&model); err != nil {
+		fmt.Printf("Load failed: %v\n", err)
+	} else {
+		matches := 0
+		for _, tc := range testCases {
+			if loaded.Predict(tc.age, tc.income, tc.creditScore) == dt.Predict(tc.age, tc.income, tc.creditScore) {
+				matches++
+			}
+		}
+		fmt.Printf("Reloaded model agrees on %d/%d test cases\n", matches, len(testCases))
+	}
+	if _, err := dt.PredictStrict(Sample{Features: map[string]float64{"age": 25, "credit_score": 720}}); err != nil {
+		fmt.Printf("Strict prediction failed: %v\n", err)
+	}
+	if _, err := LoadDecisionTree(strings.NewReader(`{"feature":"age","threshold":30}`)); err != nil {
+		fmt.Printf("Rejected model: %v\n", err)
+	}
+	
+	fmt.Println("\nTraining decision stumps on exam results (entropy vs Gini):")
+	var exams []Sample
+	for hours, label := range []string{"fail", "pass", "pass", "fail", "pass", "pass", "pass", "pass"} {
+		exams = append(exams, Sample{Features: map[string]float64{"hours_studied": float64(hours + 1)}, Label: label})
+	}
+	for _, criterion := range []Criterion{Entropy, Gini} {
+		trained := NewDecisionTree()
+		if err := trained.Train(exams, []string{"hours_studied"}, TrainOptions{Criterion: criterion, MaxDepth: 1}); err != nil {
+			fmt.Printf("Training failed: %v\n", err)
+			continue
+		}
+		fmt.Printf("  %-7s root split: %s <= %.1f\n", criterion, trained.root.feature, trained.root.threshold)
+		fmt.Printf("  %-7s 3 hours -> %s, 6 hours -> %s\n", criterion,
+			trained.PredictMap(map[string]float64{"hours_studied": 3}),
+			trained.PredictMap(map[string]float64{"hours_studied": 6}))
+	}
+	
+	fmt.Println("\nPruning a split caused by one noisy sample:")
+	var noisy []Sample
+	for hours := 1; hours <= 8; hours++ {
+		label := "fail"
+		if hours > 4 {
+			label = "pass"
+		}
+		noisy = append(noisy, Sample{Features: map[string]float64{"hours_studied": float64(hours)}, Label: label})
+	}
+	noisy = append(noisy, Sample{Features: map[string]float64{"hours_studied": 7.5}, Label: "fail"})
+	var validation []Sample
+	for _, hours := range []float64{1.5, 3.5, 5.5, 7.2, 7.7, 8.5} {
+		label := "fail"
+		if hours > 4 {
+			label = "pass"
+		}
+		validation = append(validation, Sample{Features: map[string]float64{"hours_studied": hours}, Label: label})
+	}
+	overfit := NewDecisionTree()
+	overfit.Train(noisy, []string{"hours_studied"}, TrainOptions{})
+	before := overfit.NodeCount()
+	overfit.Prune(validation)
+	fmt.Printf("  Nodes before pruning: %d, after: %d\n", before, overfit.NodeCount())
+	fmt.Printf("  7.6 hours -> %s\n", overfit.PredictMap(map[string]float64{"hours_studied": 7.6}))
+	
+	hardcoded := NewDecisionTree()
+	hardcoded.BuildTree()
+	hardcoded.Prune([]Sample{
+		{Features: map[string]float64{"age": 40, "income": 80000, "credit_score": 800}, Label: "approve"},
+		{Features: map[string]float64{"age": 40, "income": 30000, "credit_score": 550}, Label: "reject"},
+	})
+	fmt.Printf("  Hardcoded tree pruned on age-40 samples, 25/60000/750 -> %s\n", hardcoded.Predict(25, 60000, 750))
+	
+	stump := NewDecisionTree()
+	stump.Train(noisy, []string{"hours_studied"}, TrainOptions{MaxDepth: 1})
+	fmt.Printf("  Decision stump (MaxDepth 1):\n%s", stump)
+	fmt.Printf("  7.6 hours probabilities: %v\n", overfit.PredictProba(map[string]float64{"hours_studied": 7.6}))
+	fmt.Printf("  Hardcoded tree probabilities: %v\n", dt.PredictProba(map[string]float64{"age": 25, "income": 60000}))
+	fmt.Printf("  Untrained tree probabilities: %v\n", NewDecisionTree().PredictProba(map[string]float64{"age": 25}))
+	
+	fmt.Println("\nRandom forest vs single tree on noisy data:")
+	dataRng := rand.New(rand.NewSource(42))
+	makeSamples := func(n int, noise float64) []Sample {
+		samples := make([]Sample, n)
+		for i := range samples {
+			features := map[string]float64{
+				"x":      dataRng.Float64() * 10,
+				"y":      dataRng.Float64() * 10,
+				"noise1": dataRng.Float64() * 10,
+				"noise2": dataRng.Float64() * 10,
+			}
+			label := "low"
+			if features["x"]+features["y"] > 10 {
+				label = "high"
+			}
+			if dataRng.Float64() < noise {
+				if label == "low" {
+					label = "high"
+				} else {
+					label = "low"
+				}
+			}
+			samples[i] = Sample{Features: features, Label: label}
+		}
+		return samples
+	}
+	forestFeatures := []string{"x", "y", "noise1", "noise2"}
+	noisyTrain, cleanTest := makeSamples(300, 0.2), makeSamples(300, 0)
+	
+	single := NewDecisionTree()
+	single.Train(noisyTrain, forestFeatures, TrainOptions{})
+	forest := NewRandomForest(7)
+	if err := forest.TrainForest(noisyTrain, forestFeatures, 50, 2); err != nil {
+		fmt.Printf("Forest training failed: %v\n", err)
+	}
+	forestCorrect := 0
+	for _, sample := range cleanTest {
+		if forest.PredictSample(sample) == sample.Label {
+			forestCorrect++
+		}
+	}
+	singleResult := single.Evaluate(cleanTest)
+	fmt.Printf("  Single tree accuracy: %.1f%%\n", 100*singleResult.Accuracy)
+	for _, actual := range []string{"high", "low"} {
+		fmt.Printf("    actual %-4s -> predicted high: %3d, low: %3d\n",
+			actual, singleResult.Confusion[actual]["high"], singleResult.Confusion[actual]["low"])
+	}
+	fmt.Printf("  Random forest (50 trees) accuracy: %.1f%%\n", 100*float64(forestCorrect)/float64(len(cleanTest)))
+	bagged := NewDecisionTree()
+	bagged.Train(noisyTrain, forestFeatures, TrainOptions{FeaturesPerSplit: 2, Seed: 7})
+	fmt.Printf("  Single tree with 2 random features per split: %.1f%%\n", 100*bagged.Evaluate(cleanTest).Accuracy)
+	
+	fmt.Println("\n5-fold cross-validation of a single tree on noisy data:")
+	folds, err := CrossValidate(noisyTrain, forestFeatures, 5, 1)
+	if err != nil {
+		fmt.Printf("Cross-validation failed: %v\n", err)
+	}
+	mean := 0.0
+	for i, accuracy := range folds {
+		fmt.Printf("  Fold %d: %.1f%%\n", i+1, 100*accuracy)
+		mean += accuracy
+	}
+	fmt.Printf("  Mean accuracy: %.1f%%\n", 100*mean/float64(len(folds)))
+	if _, err := CrossValidate(noisyTrain, forestFeatures, 1, 1); err != nil {
+		fmt.Printf("  Rejected: %v\n", err)
+	}
+	
+	fmt.Println("\nRegression tree on a piecewise-linear target:")
+	target := func(x float64) float64 {
+		if x < 5 {
+			return 2 * x
+		}
+		return 20 - 2*x
+	}
+	var points []RegressionSample
+	for x := 0.0; x <= 10; x += 0.1 {
+		points = append(points, RegressionSample{Features: map[string]float64{"x": x}, Target: target(x)})
+	}
+	for _, depth := range []int{1, 2, 3, 4, 6} {
+		regression := NewDecisionTree()
+		regression.TrainRegression(points, []string{"x"}, TrainOptions{MaxDepth: depth})
+		
+		totalError, probes := 0.0, 0
+		for x := 0.05; x < 10; x += 0.1 {
+			predicted, _ := regression.PredictValue(map[string]float64{"x": x})
+			totalError += math.Abs(predicted - target(x))
+			probes++
+		}
+		fmt.Printf("  MaxDepth %d, %3d nodes: mean error %.3f\n", depth, regression.NodeCount(), totalError/float64(probes))
+	}
+	if _, ok := NewDecisionTree().PredictValue(map[string]float64{"x": 2}); !ok {
+		fmt.Println("  Untrained regression tree has no prediction")
+	}
+	
+	fmt.Println("\nTraining with a categorical employment_type feature:")
+	applicants := []Sample{
+		{Features: map[string]float64{"income": 30000}, Categories: map[string]string{"employment_type": "salaried"}, Label: "approve"},
+		{Features: map[string]float64{"income": 85000}, Categories: map[string]string{"employment_type": "salaried"}, Label: "approve"},
+		{Features: map[string]float64{"income": 40000}, Categories: map[string]string{"employment_type": "contractor"}, Label: "reject"},
+		{Features: map[string]float64{"income": 95000}, Categories: map[string]string{"employment_type": "contractor"}, Label: "approve"},
+		{Features: map[string]float64{"income": 20000}, Categories: map[string]string{"employment_type": "unemployed"}, Label: "reject"},
+		{Features: map[string]float64{"income": 60000}, Categories: map[string]string{"employment_type": "unemployed"}, Label: "reject"},
+	}
+	loans := NewDecisionTree()
+	if err := loans.Train(applicants, []string{"employment_type", "income"}, TrainOptions{Criterion: Gini}); err != nil {
+		fmt.Printf("Training failed: %v\n", err)
+	}
+	fmt.Print(loans)
+	for _, applicant := range []Sample{
+		{Features: map[string]float64{"income": 50000}, Categories: map[string]string{"employment_type": "salaried"}},
+		{Features: map[string]float64{"income": 50000}, Categories: map[string]string{"employment_type": "contractor"}},
+		{Features: map[string]float64{"income": 120000}, Categories: map[string]string{"employment_type": "contractor"}},
+		{Features: map[string]float64{"income": 70000}, Categories: map[string]string{"employment_type": "retired"}},
+	} {
+		fmt.Printf("  %s, income %.0f -> %s\n", applicant.Categories["employment_type"], applicant.Features["income"], loans.PredictSample(applicant))
+	}
+	if label, err := loans.PredictStrict(Sample{Features: map[string]float64{"income": 50000}, Categories: map[string]string{"employment_type": "salaried"}}); err != nil {
+		fmt.Printf("  Strict prediction failed: %v\n", err)
+	} else {
+		fmt.Printf("  Strict prediction for salaried, income 50000 -> %s\n", label)
+	}
+	if _, err := loans.PredictStrict(Sample{Features: map[string]float64{"income": 50000}}); err != nil {
+		fmt.Printf("  Strict prediction without employment_type failed: %v\n", err)
+	}
 }
\ No newline at end of file
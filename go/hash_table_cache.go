@@ -1,70 +1,423 @@
 package main
 
 import (
+	"container/list"
 	"crypto/sha256"
 	"fmt"
+	"sort"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
-type CacheItem struct {
-	value      interface{}
+type CacheItem[K comparable, V any] struct {
+	key        K
+	value      V
 	expiration int64
+	size       int
+	frequency  int
+	sliding    bool
+	ttl        time.Duration
+	element    *list.Element
 }
 
-type LRUCache struct {
+func (item *CacheItem[K, V]) expired(now int64) bool {
+	return item.expiration > 0 && now > item.expiration
+}
+
+type Cache[K comparable, V any] struct {
 	capacity int
-	cache    map[string]*CacheItem
+	cache    map[K]*CacheItem[K, V]
 	mutex    sync.RWMutex
+	hits     atomic.Uint64
+	misses   atomic.Uint64
+	order    *list.List
+	onEvict  []func(key K, value V)
+	maxBytes int
+	bytes    int
+	lfu      bool
+	inflight map[K]*cacheCall[V]
 }
 
-func NewLRUCache(capacity int) *LRUCache {
-	return &LRUCache{
+type cacheCall[V any] struct {
+	wg    sync.WaitGroup
+	value V
+	err   error
+}
+
+func NewCache[K comparable, V any](capacity int) *Cache[K, V] {
+	return &Cache[K, V]{
 		capacity: capacity,
-		cache:    make(map[string]*CacheItem),
+		cache:    make(map[K]*CacheItem[K, V]),
+		order:    list.New(),
 	}
 }
 
-func (c *LRUCache) Get(key string) (interface{}, bool) {
+func NewCacheBytes[K comparable, V any](maxBytes int) *Cache[K, V] {
+	c := NewCache[K, V](0)
+	c.maxBytes = maxBytes
+	return c
+}
+
+func NewLFUCacheOf[K comparable, V any](capacity int) *Cache[K, V] {
+	c := NewCache[K, V](capacity)
+	c.lfu = true
+	return c
+}
+
+func (c *Cache[K, V]) Bytes() int {
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
 	
+	return c.bytes
+}
+
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	var evicted []*CacheItem[K, V]
+	defer func() { c.notifyEvicted(evicted) }()
+	
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	
+	return c.get(key, time.Now().UnixNano(), &evicted)
+}
+
+func (c *Cache[K, V]) get(key K, now int64, evicted *[]*CacheItem[K, V]) (V, bool) {
+	var zero V
 	item, exists := c.cache[key]
 	if !exists {
-		return nil, false
+		c.misses.Add(1)
+		return zero, false
 	}
 	
-	if item.expiration > 0 && time.Now().Unix() > item.expiration {
-		delete(c.cache, key)
-		return nil, false
+	if item.expired(now) {
+		c.removeItem(item)
+		*evicted = append(*evicted, item)
+		c.misses.Add(1)
+		return zero, false
 	}
 	
+	c.order.MoveToFront(item.element)
+	item.frequency++
+	if item.sliding {
+		item.expiration = time.Unix(0, now).Add(item.ttl).UnixNano()
+	}
+	c.hits.Add(1)
 	return item.value, true
 }
 
-func (c *LRUCache) Set(key string, value interface{}, ttl time.Duration) {
+func (c *Cache[K, V]) MGet(keys []K) map[K]V {
+	var evicted []*CacheItem[K, V]
+	defer func() { c.notifyEvicted(evicted) }()
+	
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	
+	now := time.Now().UnixNano()
+	values := make(map[K]V, len(keys))
+	for _, key := range keys {
+		if value, found := c.get(key, now, &evicted); found {
+			values[key] = value
+		}
+	}
+	return values
+}
+
+func (c *Cache[K, V]) Peek(key K) (V, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	
+	item, exists := c.cache[key]
+	if !exists || item.expired(time.Now().UnixNano()) {
+		var zero V
+		return zero, false
+	}
+	return item.value, true
+}
+
+func (c *Cache[K, V]) GetOrCompute(key K, ttl time.Duration, fn func() (V, error)) (V, error) {
+	if value, found := c.Get(key); found {
+		return value, nil
+	}
+	
+	c.mutex.Lock()
+	if item, exists := c.cache[key]; exists && !item.expired(time.Now().UnixNano()) {
+		value := item.value
+		c.mutex.Unlock()
+		return value, nil
+	}
+	if call, pending := c.inflight[key]; pending {
+		c.mutex.Unlock()
+		call.wg.Wait()
+		return call.value, call.err
+	}
+	call := &cacheCall[V]{err: fmt.Errorf("computing %v panicked", key)}
+	call.wg.Add(1)
+	if c.inflight == nil {
+		c.inflight = make(map[K]*cacheCall[V])
+	}
+	c.inflight[key] = call
+	c.mutex.Unlock()
+	
+	defer func() {
+		c.mutex.Lock()
+		delete(c.inflight, key)
+		c.mutex.Unlock()
+		call.wg.Done()
+	}()
+	
+	call.value, call.err = fn()
+	if call.err == nil {
+		c.Set(key, call.value, ttl)
+	}
+	return call.value, call.err
+}
+
+func (c *Cache[K, V]) OnEvict(callback func(key K, value V)) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	
+	c.onEvict = append(c.onEvict, callback)
+}
+
+func (c *Cache[K, V]) notifyEvicted(items []*CacheItem[K, V]) {
+	if len(items) == 0 {
+		return
+	}
+	
+	c.mutex.RLock()
+	callbacks := append([]func(K, V){}, c.onEvict...)
+	c.mutex.RUnlock()
+	
+	for _, item := range items {
+		for _, callback := range callbacks {
+			callback(item.key, item.value)
+		}
+	}
+}
+
+func (c *Cache[K, V]) removeItem(item *CacheItem[K, V]) {
+	delete(c.cache, item.key)
+	c.order.Remove(item.element)
+	c.bytes -= item.size
+}
+
+func (c *Cache[K, V]) evictOverflow() []*CacheItem[K, V] {
+	var evicted []*CacheItem[K, V]
+	for (c.capacity > 0 && c.order.Len() > c.capacity) || (c.maxBytes > 0 && c.bytes > c.maxBytes) {
+		victim := c.victim()
+		c.removeItem(victim)
+		evicted = append(evicted, victim)
+	}
+	return evicted
+}
+
+func (c *Cache[K, V]) victim() *CacheItem[K, V] {
+	oldest := c.order.Back()
+	if !c.lfu {
+		return oldest.Value.(*CacheItem[K, V])
+	}
+	
+	victim := oldest.Value.(*CacheItem[K, V])
+	for element := oldest.Prev(); element != nil && element != c.order.Front(); element = element.Prev() {
+		if item := element.Value.(*CacheItem[K, V]); item.frequency < victim.frequency {
+			victim = item
+		}
+	}
+	return victim
+}
+
+func (c *Cache[K, V]) Stats() (hits, misses uint64) {
+	return c.hits.Load(), c.misses.Load()
+}
+
+func (c *Cache[K, V]) ResetStats() {
+	c.hits.Store(0)
+	c.misses.Store(0)
+}
+
+func (c *Cache[K, V]) Set(key K, value V, ttl time.Duration) {
+	c.SetWithSize(key, value, 0, ttl)
+}
+
+func (c *Cache[K, V]) SetWithSize(key K, value V, size int, ttl time.Duration) {
+	var evicted []*CacheItem[K, V]
+	defer func() { c.notifyEvicted(evicted) }()
+	
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 	
+	evicted = c.set(key, value, size, ttl)
+}
+
+func (c *Cache[K, V]) SetSliding(key K, value V, ttl time.Duration) {
+	var evicted []*CacheItem[K, V]
+	defer func() { c.notifyEvicted(evicted) }()
+	
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	
+	evicted = c.set(key, value, 0, ttl)
+	if item, exists := c.cache[key]; exists && ttl > 0 {
+		item.sliding = true
+	}
+}
+
+func (c *Cache[K, V]) MSet(items map[K]V, ttl time.Duration) {
+	var evicted []*CacheItem[K, V]
+	defer func() { c.notifyEvicted(evicted) }()
+	
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	
+	for key, value := range items {
+		evicted = append(evicted, c.set(key, value, 0, ttl)...)
+	}
+}
+
+func (c *Cache[K, V]) set(key K, value V, size int, ttl time.Duration) []*CacheItem[K, V] {
 	expiration := int64(0)
 	if ttl > 0 {
-		expiration = time.Now().Add(ttl).Unix()
+		expiration = time.Now().Add(ttl).UnixNano()
+	}
+	
+	if item, exists := c.cache[key]; exists {
+		c.bytes += size - item.size
+		item.value = value
+		item.expiration = expiration
+		item.size = size
+		item.sliding = false
+		item.ttl = ttl
+		item.frequency++
+		c.order.MoveToFront(item.element)
+		return c.evictOverflow()
 	}
 	
-	c.cache[key] = &CacheItem{
+	item := &CacheItem[K, V]{
+		key:        key,
 		value:      value,
 		expiration: expiration,
+		size:       size,
+		frequency:  1,
+		ttl:        ttl,
 	}
+	item.element = c.order.PushFront(item)
+	c.cache[key] = item
+	c.bytes += size
+	return c.evictOverflow()
+}
+
+func (c *Cache[K, V]) Delete(key K) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	
+	item, exists := c.cache[key]
+	if !exists {
+		return false
+	}
+	c.removeItem(item)
+	return true
+}
+
+func (c *Cache[K, V]) Resize(newCapacity int) {
+	var evicted []*CacheItem[K, V]
+	defer func() { c.notifyEvicted(evicted) }()
+	
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	
+	c.capacity = newCapacity
+	evicted = c.evictOverflow()
+}
+
+func (c *Cache[K, V]) Range(fn func(key K, value V) bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	
+	now := time.Now().UnixNano()
+	for key, item := range c.cache {
+		if item.expired(now) {
+			continue
+		}
+		if !fn(key, item.value) {
+			return
+		}
+	}
+}
+
+func (c *Cache[K, V]) Clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	
+	c.cache = make(map[K]*CacheItem[K, V])
+	c.order.Init()
+	c.bytes = 0
+}
+
+func (c *Cache[K, V]) Len() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	
+	now := time.Now().UnixNano()
+	count := 0
+	for _, item := range c.cache {
+		if !item.expired(now) {
+			count++
+		}
+	}
+	return count
+}
+
+func (c *Cache[K, V]) Keys() []K {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	
+	now := time.Now().UnixNano()
+	keys := make([]K, 0, len(c.cache))
+	for element := c.order.Front(); element != nil; element = element.Next() {
+		if item := element.Value.(*CacheItem[K, V]); !item.expired(now) {
+			keys = append(keys, item.key)
+		}
+	}
+	return keys
+}
+
+type LRUCache struct {
+	*Cache[string, interface{}]
+}
+
+func NewLRUCache(capacity int) *LRUCache {
+	return &LRUCache{NewCache[string, interface{}](capacity)}
+}
+
+func NewLRUCacheBytes(maxBytes int) *LRUCache {
+	return &LRUCache{NewCacheBytes[string, interface{}](maxBytes)}
+}
+
+type LFUCache struct {
+	*Cache[string, interface{}]
+}
+
+func NewLFUCache(capacity int) *LFUCache {
+	return &LFUCache{NewLFUCacheOf[string, interface{}](capacity)}
+}
+
+type numericEntry struct {
+	value int
+	id    int
 }
 
 type DatabaseIndex struct {
-	index map[string][]int
-	mutex sync.RWMutex
+	index   map[string][]int
+	numeric map[string][]numericEntry
+	mutex   sync.RWMutex
 }
 
 func NewDatabaseIndex() *DatabaseIndex {
 	return &DatabaseIndex{
-		index: make(map[string][]int),
+		index:   make(map[string][]int),
+		numeric: make(map[string][]numericEntry),
 	}
 }
 
@@ -74,6 +427,57 @@ func (db *DatabaseIndex) AddRecord(id int, field string, value string) {
 	
 	key := fmt.Sprintf("%s:%s", field, value)
 	db.index[key] = append(db.index[key], id)
+	
+	if n, err := strconv.Atoi(value); err == nil {
+		entries := db.numeric[field]
+		i := sort.Search(len(entries), func(i int) bool {
+			return entries[i].value > n
+		})
+		entries = append(entries, numericEntry{})
+		copy(entries[i+1:], entries[i:])
+		entries[i] = numericEntry{value: n, id: id}
+		db.numeric[field] = entries
+	}
+}
+
+func (db *DatabaseIndex) RemoveRecord(id int, field, value string) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	
+	key := fmt.Sprintf("%s:%s", field, value)
+	ids := db.index[key]
+	for i, existing := range ids {
+		if existing == id {
+			remaining := make([]int, 0, len(ids)-1)
+			remaining = append(remaining, ids[:i]...)
+			ids = append(remaining, ids[i+1:]...)
+			break
+		}
+	}
+	
+	if len(ids) == 0 {
+		delete(db.index, key)
+	} else {
+		db.index[key] = ids
+	}
+	
+	if n, err := strconv.Atoi(value); err == nil {
+		entries := db.numeric[field]
+		i := sort.Search(len(entries), func(i int) bool {
+			return entries[i].value >= n
+		})
+		for ; i < len(entries) && entries[i].value == n; i++ {
+			if entries[i].id == id {
+				entries = append(entries[:i], entries[i+1:]...)
+				break
+			}
+		}
+		if len(entries) == 0 {
+			delete(db.numeric, field)
+		} else {
+			db.numeric[field] = entries
+		}
+	}
 }
 
 func (db *DatabaseIndex) FindRecords(field string, value string) []int {
@@ -84,6 +488,22 @@ func (db *DatabaseIndex) FindRecords(field string, value string) []int {
 	return db.index[key]
 }
 
+func (db *DatabaseIndex) FindRange(field string, lo, hi int) []int {
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+	
+	entries := db.numeric[field]
+	start := sort.Search(len(entries), func(i int) bool {
+		return entries[i].value >= lo
+	})
+	
+	var ids []int
+	for i := start; i < len(entries) && entries[i].value <= hi; i++ {
+		ids = append(ids, entries[i].id)
+	}
+	return ids
+}
+
 type PasswordManager struct {
 	passwords map[string]string
 	mutex     sync.RWMutex
@@ -130,6 +550,175 @@ func main() {
 	if value, found := cache.Get("user:123"); found {
 		fmt.Printf("Cached user: %s\n", value)
 	}
+	
+	fmt.Printf("Deleted user:123: %t\n", cache.Delete("user:123"))
+	if _, found := cache.Get("user:123"); !found {
+		fmt.Println("user:123 no longer cached")
+	}
+	fmt.Printf("Deleted missing key: %t\n", cache.Delete("user:999"))
+	
+	cache.Set("flash:promo", "50% off", 50*time.Millisecond)
+	fmt.Printf("Cache occupancy: %d %v\n", cache.Len(), cache.Keys())
+	time.Sleep(100 * time.Millisecond)
+	fmt.Printf("After promo expired: %d %v\n", cache.Len(), cache.Keys())
+	
+	cache.ResetStats()
+	for _, key := range []string{"session:abc", "user:123", "session:abc", "flash:promo", "session:abc"} {
+		cache.Get(key)
+	}
+	hits, misses := cache.Stats()
+	fmt.Printf("Hits: %d, misses: %d, hit rate: %.0f%%\n", hits, misses, 100*float64(hits)/float64(hits+misses))
+	
+	small := NewLRUCache(2)
+	small.OnEvict(func(key string, value interface{}) {
+		fmt.Printf("Evicted %s = %v\n", key, value)
+	})
+	small.Set("a", 1, 0)
+	small.Set("b", 2, 0)
+	small.Get("a")
+	small.Set("c", 3, 0)
+	fmt.Printf("Remaining keys: %v\n", small.Keys())
+	
+	blobs := NewLRUCacheBytes(1000)
+	blobs.OnEvict(func(key string, value interface{}) {
+		fmt.Printf("Evicted %s\n", key)
+	})
+	for i := 1; i <= 5; i++ {
+		blobs.SetWithSize(fmt.Sprintf("thumb:%d", i), "small image", 150, 0)
+	}
+	fmt.Printf("Cached %d thumbnails using %d bytes\n", blobs.Len(), blobs.Bytes())
+	blobs.SetWithSize("video:1", "large clip", 600, 0)
+	fmt.Printf("After large insert: %v using %d bytes\n", blobs.Keys(), blobs.Bytes())
+	
+	scores := NewCache[int, float64](3)
+	scores.Set(42, 98.5, time.Minute)
+	scores.Set(7, 71.25, time.Minute)
+	if score, found := scores.Get(42); found {
+		fmt.Printf("Typed cache hit for player 42: %.2f\n", score)
+	}
+	if _, found := scores.Get(99); !found {
+		fmt.Println("Typed cache miss for player 99")
+	}
+	
+	lfu := NewLFUCache(3)
+	lfu.OnEvict(func(key string, value interface{}) {
+		fmt.Printf("LFU evicted %s\n", key)
+	})
+	lfu.Set("homepage", "<html>", 0)
+	for i := 0; i < 5; i++ {
+		lfu.Get("homepage")
+	}
+	for _, key := range []string{"page:1", "page:2", "page:3", "page:4"} {
+		lfu.Set(key, "<html>", 0)
+		lfu.Get(key)
+	}
+	fmt.Printf("LFU keys: %v\n", lfu.Keys())
+	
+	ports := NewLFUCacheOf[int, string](2)
+	ports.Set(443, "https", 0)
+	ports.Set(22, "ssh", 0)
+	ports.Get(443)
+	ports.Get(443)
+	ports.Get(22)
+	ports.Set(80, "http", 0)
+	fmt.Printf("Typed LFU cache keys: %v\n", ports.Keys())
+	
+	peeked := NewLRUCache(2)
+	peeked.Set("x", "first", 0)
+	peeked.Set("y", "second", 0)
+	if value, found := peeked.Peek("x"); found {
+		fmt.Printf("Peeked x = %v\n", value)
+	}
+	peeked.Set("z", "third", 0)
+	_, xFound := peeked.Peek("x")
+	hits, misses = peeked.Stats()
+	fmt.Printf("x still cached after peek and insert: %t (hits %d, misses %d)\n", xFound, hits, misses)
+	
+	var computations atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cache.GetOrCompute("report:monthly", time.Minute, func() (interface{}, error) {
+				computations.Add(1)
+				time.Sleep(50 * time.Millisecond)
+				return "expensive report", nil
+			})
+		}()
+	}
+	wg.Wait()
+	report, _ := cache.GetOrCompute("report:monthly", time.Minute, func() (interface{}, error) {
+		return nil, fmt.Errorf("should have been cached")
+	})
+	fmt.Printf("10 concurrent callers computed %d time(s): %v\n", computations.Load(), report)
+	
+	func() {
+		defer func() {
+			fmt.Printf("Compute panicked: %v\n", recover())
+		}()
+		cache.GetOrCompute("report:yearly", time.Minute, func() (interface{}, error) {
+			panic("report backend unavailable")
+		})
+	}()
+	yearly, err := cache.GetOrCompute("report:yearly", time.Minute, func() (interface{}, error) {
+		return "yearly report", nil
+	})
+	fmt.Printf("Next caller after the panic got: %v (err: %v)\n", yearly, err)
+	
+	cache.Clear()
+	hits, misses = cache.Stats()
+	fmt.Printf("After Clear: %d entries (stats kept: %d hits, %d misses)\n", cache.Len(), hits, misses)
+	blobs.Clear()
+	fmt.Printf("After Clear: %d entries using %d bytes\n", blobs.Len(), blobs.Bytes())
+	
+	cache.MSet(map[string]interface{}{
+		"user:1": "Ada",
+		"user:2": "Grace",
+		"user:3": "Linus",
+	}, time.Minute)
+	batch := cache.MGet([]string{"user:1", "user:4", "user:3", "user:9"})
+	fmt.Printf("MGet returned %d of 4 keys: user:1=%v user:3=%v\n", len(batch), batch["user:1"], batch["user:3"])
+	
+	sessions := NewLRUCache(10)
+	sessions.SetSliding("session:active", "alice", 100*time.Millisecond)
+	sessions.Set("session:idle", "bob", 100*time.Millisecond)
+	for i := 0; i < 6; i++ {
+		time.Sleep(50 * time.Millisecond)
+		sessions.Get("session:active")
+	}
+	_, activeAlive := sessions.Get("session:active")
+	_, idleAlive := sessions.Get("session:idle")
+	fmt.Printf("After 300ms: sliding session alive: %t, fixed session alive: %t\n", activeAlive, idleAlive)
+	
+	resizable := NewLRUCache(5)
+	resizable.OnEvict(func(key string, value interface{}) {
+		fmt.Printf("Resize evicted %s\n", key)
+	})
+	for i := 1; i <= 5; i++ {
+		resizable.Set(fmt.Sprintf("k%d", i), i, 0)
+	}
+	resizable.Get("k2")
+	resizable.Resize(2)
+	fmt.Printf("After shrinking to 2: %v\n", resizable.Keys())
+	
+	dump := NewLRUCache(10)
+	dump.Set("config:theme", "dark", 0)
+	dump.Set("config:lang", "en", 0)
+	dump.Set("otp:123", "987654", 20*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	visited := 0
+	dump.Range(func(key string, value interface{}) bool {
+		visited++
+		return true
+	})
+	fmt.Printf("Range visited %d live entries (expired otp skipped)\n", visited)
+	visited = 0
+	dump.Range(func(key string, value interface{}) bool {
+		visited++
+		return false
+	})
+	fmt.Printf("Range stopped early after %d entry\n", visited)
 
 	fmt.Println("\n=== Database Indexing Example ===")
 	dbIndex := NewDatabaseIndex()
@@ -140,6 +729,21 @@ func main() {
 	
 	records := dbIndex.FindRecords("city", "New York")
 	fmt.Printf("Records in New York: %v\n", records)
+	
+	dbIndex.RemoveRecord(3, "city", "New York")
+	fmt.Printf("After removing record 3: %v (earlier result still %v)\n", dbIndex.FindRecords("city", "New York"), records)
+	dbIndex.RemoveRecord(4, "city", "New York")
+	fmt.Printf("After removing record 4: %v (key dropped: %t)\n",
+		dbIndex.FindRecords("city", "New York"), len(dbIndex.index) == 2)
+	
+	ages := map[int]int{5: 34, 6: 19, 7: 52, 8: 27, 9: 41, 10: 27}
+	for id := 5; id <= 10; id++ {
+		dbIndex.AddRecord(id, "age", strconv.Itoa(ages[id]))
+	}
+	fmt.Printf("Records aged 25-45: %v\n", dbIndex.FindRange("age", 25, 45))
+	dbIndex.RemoveRecord(8, "age", "27")
+	fmt.Printf("Records aged 25-45 after removing 8: %v\n", dbIndex.FindRange("age", 25, 45))
+	fmt.Printf("Records aged 60-99: %v\n", dbIndex.FindRange("age", 60, 99))
 
 	fmt.Println("\n=== Password Storage Example ===")
 	pm := NewPasswordManager()
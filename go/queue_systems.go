@@ -1,7 +1,17 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -13,37 +23,75 @@ type PrintJob struct {
 	Pages    int
 	Priority int
 	UserID   string
+	Status   string
+	sequence int
 }
 
 type PrintQueue struct {
-	jobs []PrintJob
-	mu   sync.Mutex
+	jobs           []PrintJob
+	mu             sync.RWMutex
+	PagesPerMinute int
+	quotas         map[string]int
+	userPages      map[string]int
+	capacity       int
+	statuses       map[int]string
+	nextSequence   int
 }
 
 func NewPrintQueue() *PrintQueue {
 	return &PrintQueue{
-		jobs: make([]PrintJob, 0),
+		jobs:           make([]PrintJob, 0),
+		PagesPerMinute: 20,
+		quotas:         make(map[string]int),
+		userPages:      make(map[string]int),
+		statuses:       make(map[int]string),
 	}
 }
 
-func (pq *PrintQueue) AddJob(job PrintJob) {
+func NewPrintQueueWithCapacity(max int) *PrintQueue {
+	pq := NewPrintQueue()
+	pq.capacity = max
+	return pq
+}
+
+func (pq *PrintQueue) SetUserQuota(userID string, maxPages int) {
 	pq.mu.Lock()
 	defer pq.mu.Unlock()
 	
-	inserted := false
-	for i, existingJob := range pq.jobs {
-		if job.Priority > existingJob.Priority {
-			pq.jobs = append(pq.jobs[:i], append([]PrintJob{job}, pq.jobs[i:]...)...)
-			inserted = true
-			break
-		}
-	}
+	pq.quotas[userID] = maxPages
+}
+
+func (pq *PrintQueue) AddJob(job PrintJob) error {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
 	
-	if !inserted {
-		pq.jobs = append(pq.jobs, job)
+	if pq.capacity > 0 && len(pq.jobs) >= pq.capacity {
+		return fmt.Errorf("print queue full: capacity %d reached, job %d rejected", pq.capacity, job.ID)
+	}
+	if quota, ok := pq.quotas[job.UserID]; ok && pq.userPages[job.UserID]+job.Pages > quota {
+		return fmt.Errorf("job %d would exceed quota for %s: %d of %d pages used",
+			job.ID, job.UserID, pq.userPages[job.UserID], quota)
 	}
 	
+	job.Status = "queued"
+	job.sequence = pq.nextSequence
+	pq.nextSequence++
+	pq.insertJob(job)
+	pq.userPages[job.UserID] += job.Pages
+	pq.statuses[job.ID] = job.Status
 	fmt.Printf("Added print job: %s (Priority: %d)\n", job.Document, job.Priority)
+	return nil
+}
+
+func (pq *PrintQueue) insertJob(job PrintJob) {
+	i := sort.Search(len(pq.jobs), func(i int) bool {
+		existingJob := pq.jobs[i]
+		if existingJob.Priority != job.Priority {
+			return existingJob.Priority < job.Priority
+		}
+		return existingJob.sequence > job.sequence
+	})
+	pq.jobs = append(pq.jobs[:i], append([]PrintJob{job}, pq.jobs[i:]...)...)
 }
 
 func (pq *PrintQueue) ProcessNext() *PrintJob {
@@ -56,13 +104,124 @@ func (pq *PrintQueue) ProcessNext() *PrintJob {
 	
 	job := pq.jobs[0]
 	pq.jobs = pq.jobs[1:]
+	job.Status = "printing"
+	pq.statuses[job.ID] = job.Status
 	return &job
 }
 
-func (pq *PrintQueue) GetStatus() {
+func (pq *PrintQueue) CompleteJob(id int) bool {
 	pq.mu.Lock()
 	defer pq.mu.Unlock()
 	
+	if pq.statuses[id] != "printing" {
+		return false
+	}
+	pq.statuses[id] = "done"
+	return true
+}
+
+func (pq *PrintQueue) JobStatus(id int) (string, bool) {
+	pq.mu.RLock()
+	defer pq.mu.RUnlock()
+	
+	status, ok := pq.statuses[id]
+	return status, ok
+}
+
+func (pq *PrintQueue) Peek() *PrintJob {
+	pq.mu.RLock()
+	defer pq.mu.RUnlock()
+	
+	if len(pq.jobs) == 0 {
+		return nil
+	}
+	
+	job := pq.jobs[0]
+	return &job
+}
+
+func (pq *PrintQueue) CancelJob(id int) bool {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	
+	for i, job := range pq.jobs {
+		if job.ID == id {
+			pq.jobs = append(pq.jobs[:i], pq.jobs[i+1:]...)
+			pq.userPages[job.UserID] -= job.Pages
+			pq.statuses[id] = "cancelled"
+			return true
+		}
+	}
+	return false
+}
+
+func (pq *PrintQueue) RemoveByUser(userID string) int {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	
+	remaining := pq.jobs[:0]
+	removed := 0
+	for _, job := range pq.jobs {
+		if job.UserID == userID {
+			pq.userPages[job.UserID] -= job.Pages
+			pq.statuses[job.ID] = "cancelled"
+			removed++
+			continue
+		}
+		remaining = append(remaining, job)
+	}
+	pq.jobs = remaining
+	return removed
+}
+
+func (pq *PrintQueue) Reprioritize(id int, newPriority int) bool {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	
+	for i, job := range pq.jobs {
+		if job.ID == id {
+			pq.jobs = append(pq.jobs[:i], pq.jobs[i+1:]...)
+			job.Priority = newPriority
+			pq.insertJob(job)
+			return true
+		}
+	}
+	return false
+}
+
+func (pq *PrintQueue) EstimatedWait(id int) (time.Duration, error) {
+	pq.mu.RLock()
+	defer pq.mu.RUnlock()
+	
+	if pq.PagesPerMinute <= 0 {
+		return 0, fmt.Errorf("print rate must be positive, got %d pages/minute", pq.PagesPerMinute)
+	}
+	
+	pages := 0
+	for _, job := range pq.jobs {
+		pages += job.Pages
+		if job.ID == id {
+			return time.Duration(pages) * time.Minute / time.Duration(pq.PagesPerMinute), nil
+		}
+	}
+	return 0, fmt.Errorf("job %d is not queued", id)
+}
+
+func (pq *PrintQueue) PendingPages() int {
+	pq.mu.RLock()
+	defer pq.mu.RUnlock()
+	
+	total := 0
+	for _, job := range pq.jobs {
+		total += job.Pages
+	}
+	return total
+}
+
+func (pq *PrintQueue) GetStatus() {
+	pq.mu.RLock()
+	defer pq.mu.RUnlock()
+	
 	fmt.Printf("Print Queue Status - %d jobs pending:\n", len(pq.jobs))
 	for i, job := range pq.jobs {
 		fmt.Printf("  %d. %s (%d pages, Priority: %d) - User: %s\n", 
@@ -71,11 +230,21 @@ func (pq *PrintQueue) GetStatus() {
 }
 
 type Task struct {
-	ID        int
-	Name      string
-	Priority  int
-	Duration  time.Duration
-	CreatedAt time.Time
+	ID          int
+	Name        string
+	Priority    int
+	Duration    time.Duration
+	Remaining   time.Duration
+	CreatedAt   time.Time
+	StartedAt   time.Time
+	CompletedAt time.Time
+	Deadline    time.Time
+}
+
+type SchedulerMetrics struct {
+	AverageWait       time.Duration
+	AverageTurnaround time.Duration
+	MissedDeadlines   int
 }
 
 type CPUScheduler struct {
@@ -84,6 +253,14 @@ type CPUScheduler struct {
 	completedTasks []Task
 	mu           sync.RWMutex
 	isRunning    bool
+	segmentStart time.Time
+	segments     []ganttSegment
+	onComplete   []func(Task)
+}
+
+type ganttSegment struct {
+	name       string
+	start, end time.Time
 }
 
 func NewCPUScheduler() *CPUScheduler {
@@ -98,7 +275,16 @@ func (cs *CPUScheduler) AddTask(task Task) {
 	defer cs.mu.Unlock()
 	
 	task.CreatedAt = time.Now()
+	if task.Remaining == 0 {
+		task.Remaining = task.Duration
+	}
 	
+	cs.insertTask(task)
+	fmt.Printf("Task added to scheduler: %s (Priority: %d, Duration: %v)\n", 
+		task.Name, task.Priority, task.Duration)
+}
+
+func (cs *CPUScheduler) insertTask(task Task) {
 	inserted := false
 	for i, existingTask := range cs.readyQueue {
 		if task.Priority > existingTask.Priority {
@@ -108,99 +294,966 @@ func (cs *CPUScheduler) AddTask(task Task) {
 		}
 	}
 	
-	if !inserted {
-		cs.readyQueue = append(cs.readyQueue, task)
+	if !inserted {
+		cs.readyQueue = append(cs.readyQueue, task)
+	}
+}
+
+func (cs *CPUScheduler) startTask(task *Task) {
+	if task.StartedAt.IsZero() {
+		task.StartedAt = time.Now()
+	}
+	cs.currentTask = task
+	cs.segmentStart = time.Now()
+}
+
+func (cs *CPUScheduler) releaseCPU() {
+	if cs.currentTask == nil {
+		return
+	}
+	cs.segments = append(cs.segments, ganttSegment{cs.currentTask.Name, cs.segmentStart, time.Now()})
+	cs.currentTask = nil
+}
+
+func (cs *CPUScheduler) finishTask(task Task) Task {
+	task.Remaining = 0
+	task.CompletedAt = time.Now()
+	cs.completedTasks = append(cs.completedTasks, task)
+	cs.releaseCPU()
+	return task
+}
+
+func (cs *CPUScheduler) OnComplete(callback func(Task)) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	
+	cs.onComplete = append(cs.onComplete, callback)
+}
+
+func (cs *CPUScheduler) notifyComplete(task Task) {
+	cs.mu.RLock()
+	callbacks := append([]func(Task){}, cs.onComplete...)
+	cs.mu.RUnlock()
+	
+	for _, callback := range callbacks {
+		callback(task)
+	}
+}
+
+func (cs *CPUScheduler) RunScheduler() {
+	cs.mu.Lock()
+	if cs.isRunning {
+		cs.mu.Unlock()
+		return
+	}
+	cs.isRunning = true
+	cs.mu.Unlock()
+
+	cs.runPriorityLoop()
+}
+
+func (cs *CPUScheduler) Start() (<-chan struct{}, error) {
+	cs.mu.Lock()
+	if cs.isRunning {
+		cs.mu.Unlock()
+		return nil, fmt.Errorf("scheduler is already running")
+	}
+	cs.isRunning = true
+	cs.mu.Unlock()
+	
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cs.runPriorityLoop()
+	}()
+	return done, nil
+}
+
+func (cs *CPUScheduler) runPriorityLoop() {
+	for {
+		cs.mu.Lock()
+		if len(cs.readyQueue) == 0 {
+			cs.isRunning = false
+			cs.mu.Unlock()
+			break
+		}
+		
+		task := cs.readyQueue[0]
+		cs.readyQueue = cs.readyQueue[1:]
+		cs.startTask(&task)
+		cs.mu.Unlock()
+		
+		fmt.Printf("Executing task: %s (Duration: %v)\n", task.Name, task.Remaining)
+		time.Sleep(task.Remaining)
+		
+		cs.mu.Lock()
+		task = cs.finishTask(task)
+		cs.mu.Unlock()
+		cs.notifyComplete(task)
+		
+		fmt.Printf("Task completed: %s\n", task.Name)
+	}
+}
+
+func (cs *CPUScheduler) RunWithContext(ctx context.Context) {
+	cs.mu.Lock()
+	if cs.isRunning {
+		cs.mu.Unlock()
+		return
+	}
+	cs.isRunning = true
+	cs.mu.Unlock()
+	
+	defer func() {
+		cs.mu.Lock()
+		cs.isRunning = false
+		cs.mu.Unlock()
+	}()
+
+	for {
+		if ctx.Err() != nil {
+			fmt.Printf("Scheduler stopped: %v\n", ctx.Err())
+			return
+		}
+		
+		cs.mu.Lock()
+		if len(cs.readyQueue) == 0 {
+			cs.mu.Unlock()
+			return
+		}
+		
+		task := cs.readyQueue[0]
+		cs.readyQueue = cs.readyQueue[1:]
+		cs.startTask(&task)
+		cs.mu.Unlock()
+		
+		fmt.Printf("Executing task: %s (Duration: %v)\n", task.Name, task.Remaining)
+		started := time.Now()
+		timer := time.NewTimer(task.Remaining)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			cs.mu.Lock()
+			task.Remaining -= time.Since(started)
+			cs.releaseCPU()
+			finished := task.Remaining <= 0
+			if finished {
+				task = cs.finishTask(task)
+			} else if len(cs.readyQueue) == 0 || cs.readyQueue[0].Priority <= task.Priority {
+				cs.readyQueue = append([]Task{task}, cs.readyQueue...)
+			} else {
+				cs.insertTask(task)
+			}
+			cs.mu.Unlock()
+			if finished {
+				cs.notifyComplete(task)
+			}
+			fmt.Printf("Scheduler stopped during %s: %v\n", task.Name, ctx.Err())
+			return
+		}
+		
+		cs.mu.Lock()
+		task = cs.finishTask(task)
+		cs.mu.Unlock()
+		cs.notifyComplete(task)
+		
+		fmt.Printf("Task completed: %s\n", task.Name)
+	}
+}
+
+func (cs *CPUScheduler) RunRoundRobin(quantum time.Duration) {
+	if quantum <= 0 {
+		fmt.Printf("Round robin needs a positive quantum, got %v\n", quantum)
+		return
+	}
+	
+	cs.mu.Lock()
+	if cs.isRunning {
+		cs.mu.Unlock()
+		return
+	}
+	cs.isRunning = true
+	cs.mu.Unlock()
+
+	for {
+		cs.mu.Lock()
+		if len(cs.readyQueue) == 0 {
+			cs.isRunning = false
+			cs.mu.Unlock()
+			break
+		}
+		
+		task := cs.readyQueue[0]
+		cs.readyQueue = cs.readyQueue[1:]
+		cs.startTask(&task)
+		cs.mu.Unlock()
+		
+		slice := quantum
+		if task.Remaining < slice {
+			slice = task.Remaining
+		}
+		fmt.Printf("Running task: %s for %v (remaining: %v)\n", task.Name, slice, task.Remaining)
+		time.Sleep(slice)
+		task.Remaining -= slice
+		
+		cs.mu.Lock()
+		cs.releaseCPU()
+		if task.Remaining > 0 {
+			cs.readyQueue = append(cs.readyQueue, task)
+			cs.mu.Unlock()
+			continue
+		}
+		task = cs.finishTask(task)
+		cs.mu.Unlock()
+		cs.notifyComplete(task)
+		
+		fmt.Printf("Task completed: %s\n", task.Name)
+	}
+}
+
+func (cs *CPUScheduler) RunEDF() {
+	cs.mu.Lock()
+	if cs.isRunning {
+		cs.mu.Unlock()
+		return
+	}
+	cs.isRunning = true
+	cs.mu.Unlock()
+
+	for {
+		cs.mu.Lock()
+		if len(cs.readyQueue) == 0 {
+			cs.isRunning = false
+			cs.mu.Unlock()
+			break
+		}
+		
+		next := 0
+		for i, candidate := range cs.readyQueue {
+			if candidate.Deadline.IsZero() {
+				continue
+			}
+			if best := cs.readyQueue[next].Deadline; best.IsZero() || candidate.Deadline.Before(best) {
+				next = i
+			}
+		}
+		task := cs.readyQueue[next]
+		cs.readyQueue = append(cs.readyQueue[:next], cs.readyQueue[next+1:]...)
+		cs.startTask(&task)
+		cs.mu.Unlock()
+		
+		fmt.Printf("Executing task: %s (Duration: %v)\n", task.Name, task.Remaining)
+		time.Sleep(task.Remaining)
+		
+		cs.mu.Lock()
+		task = cs.finishTask(task)
+		cs.mu.Unlock()
+		cs.notifyComplete(task)
+		
+		fmt.Printf("Task completed: %s\n", task.Name)
+	}
+}
+
+func (cs *CPUScheduler) RunMultiCore(cores int) {
+	cs.mu.Lock()
+	if cs.isRunning || cores <= 0 {
+		cs.mu.Unlock()
+		return
+	}
+	cs.isRunning = true
+	cs.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for core := 1; core <= cores; core++ {
+		wg.Add(1)
+		go func(core int) {
+			defer wg.Done()
+			for {
+				cs.mu.Lock()
+				if len(cs.readyQueue) == 0 {
+					cs.mu.Unlock()
+					return
+				}
+				
+				task := cs.readyQueue[0]
+				cs.readyQueue = cs.readyQueue[1:]
+				if task.StartedAt.IsZero() {
+					task.StartedAt = time.Now()
+				}
+				cs.mu.Unlock()
+				
+				fmt.Printf("Core %d executing task: %s (Duration: %v)\n", core, task.Name, task.Remaining)
+				time.Sleep(task.Remaining)
+				
+				cs.mu.Lock()
+				task = cs.finishTask(task)
+				cs.mu.Unlock()
+				cs.notifyComplete(task)
+				
+				fmt.Printf("Core %d completed task: %s\n", core, task.Name)
+			}
+		}(core)
+	}
+	wg.Wait()
+	
+	cs.mu.Lock()
+	cs.isRunning = false
+	cs.mu.Unlock()
+}
+
+const preemptionSlice = 10 * time.Millisecond
+
+func (cs *CPUScheduler) RunPreemptive() {
+	cs.mu.Lock()
+	if cs.isRunning {
+		cs.mu.Unlock()
+		return
+	}
+	cs.isRunning = true
+	cs.mu.Unlock()
+
+	for {
+		cs.mu.Lock()
+		if len(cs.readyQueue) == 0 {
+			cs.isRunning = false
+			cs.mu.Unlock()
+			break
+		}
+		
+		task := cs.readyQueue[0]
+		cs.readyQueue = cs.readyQueue[1:]
+		cs.startTask(&task)
+		cs.mu.Unlock()
+		
+		fmt.Printf("Executing task: %s (remaining: %v)\n", task.Name, task.Remaining)
+		preempted := false
+		for task.Remaining > 0 {
+			slice := preemptionSlice
+			if task.Remaining < slice {
+				slice = task.Remaining
+			}
+			time.Sleep(slice)
+			task.Remaining -= slice
+			
+			cs.mu.Lock()
+			if task.Remaining > 0 && len(cs.readyQueue) > 0 && cs.readyQueue[0].Priority > task.Priority {
+				fmt.Printf("Task preempted: %s by %s (remaining: %v)\n", task.Name, cs.readyQueue[0].Name, task.Remaining)
+				cs.insertTask(task)
+				cs.releaseCPU()
+				preempted = true
+			}
+			cs.mu.Unlock()
+			if preempted {
+				break
+			}
+		}
+		if preempted {
+			continue
+		}
+		
+		cs.mu.Lock()
+		task = cs.finishTask(task)
+		cs.mu.Unlock()
+		cs.notifyComplete(task)
+		
+		fmt.Printf("Task completed: %s\n", task.Name)
+	}
+}
+
+func (cs *CPUScheduler) CancelTask(id int) bool {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	
+	if cs.currentTask != nil && cs.currentTask.ID == id {
+		return false
+	}
+	for i, task := range cs.readyQueue {
+		if task.ID == id {
+			cs.readyQueue = append(cs.readyQueue[:i], cs.readyQueue[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (cs *CPUScheduler) Metrics() SchedulerMetrics {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	
+	var metrics SchedulerMetrics
+	if len(cs.completedTasks) == 0 {
+		return metrics
+	}
+	
+	var totalWait, totalTurnaround time.Duration
+	for _, task := range cs.completedTasks {
+		totalWait += task.StartedAt.Sub(task.CreatedAt)
+		totalTurnaround += task.CompletedAt.Sub(task.CreatedAt)
+		if !task.Deadline.IsZero() && task.CompletedAt.After(task.Deadline) {
+			metrics.MissedDeadlines++
+		}
+	}
+	count := time.Duration(len(cs.completedTasks))
+	metrics.AverageWait = totalWait / count
+	metrics.AverageTurnaround = totalTurnaround / count
+	return metrics
+}
+
+func (cs *CPUScheduler) GanttChart() string {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	
+	if len(cs.segments) == 0 {
+		return ""
+	}
+	
+	unit := cs.segments[0].end.Sub(cs.segments[0].start)
+	for _, segment := range cs.segments[1:] {
+		if length := segment.end.Sub(segment.start); length < unit {
+			unit = length
+		}
+	}
+	if unit <= 0 {
+		unit = time.Millisecond
+	}
+	units := func(length time.Duration) int {
+		return int((length + unit/2) / unit)
+	}
+	
+	var chart strings.Builder
+	writeBlock := func(label string, length time.Duration, fill string) {
+		width := units(length)
+		if width < 1 {
+			width = 1
+		}
+		chart.WriteString("|" + label)
+		if width > len(label) {
+			chart.WriteString(strings.Repeat(fill, width-len(label)))
+		}
+	}
+	
+	for i, segment := range cs.segments {
+		if i > 0 {
+			if gap := segment.start.Sub(cs.segments[i-1].end); units(gap) > 0 {
+				writeBlock("", gap, ".")
+			}
+		}
+		writeBlock(segment.name, segment.end.Sub(segment.start), "-")
+	}
+	chart.WriteString("|")
+	
+	total := cs.segments[len(cs.segments)-1].end.Sub(cs.segments[0].start)
+	fmt.Fprintf(&chart, "  (1 unit = %v, total %v)", unit.Round(time.Millisecond), total.Round(time.Millisecond))
+	return chart.String()
+}
+
+func (cs *CPUScheduler) GetStatus() {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	
+	fmt.Printf("CPU Scheduler Status:\n")
+	if cs.currentTask != nil {
+		fmt.Printf("  Current: %s\n", cs.currentTask.Name)
+	} else {
+		fmt.Printf("  Current: idle\n")
+	}
+	
+	fmt.Printf("  Ready Queue (%d tasks):\n", len(cs.readyQueue))
+	for i, task := range cs.readyQueue {
+		fmt.Printf("    %d. %s (Priority: %d)\n", i+1, task.Name, task.Priority)
+	}
+	
+	fmt.Printf("  Completed: %d tasks\n", len(cs.completedTasks))
+}
+
+type WebPage struct {
+	URL        string
+	Content    string
+	Links      []string
+	Depth      int
+	Visited    bool
+	FetchedAt  time.Time
+	StatusCode int
+}
+
+type WebCrawler struct {
+	queue       []WebPage
+	visited     map[string]bool
+	maxDepth    int
+	maxPages    int
+	mu          sync.RWMutex
+	crawledData []WebPage
+	userAgent   string
+	robotsTxt   map[string]*robotsEntry
+	rateLimit   time.Duration
+	lastFetch   map[string]time.Time
+	allowed     []string
+	blocked     []string
+	fetcher     Fetcher
+	maxRetries  int
+	retryDelay  time.Duration
+	failed      []string
+	elapsed     time.Duration
+}
+
+type CrawlStats struct {
+	PagesCrawled    int
+	LinksDiscovered int
+	MaxDepth        int
+	FailedFetches   int
+	Elapsed         time.Duration
+}
+
+type Fetcher interface {
+	Fetch(url string) (WebPage, error)
+}
+
+type contextFetcher interface {
+	FetchContext(ctx context.Context, url string) (WebPage, error)
+}
+
+type FetcherFunc func(url string) (WebPage, error)
+
+func (f FetcherFunc) Fetch(url string) (WebPage, error) {
+	return f(url)
+}
+
+type mockFetcher struct{}
+
+func (m mockFetcher) Fetch(url string) (WebPage, error) {
+	return m.simulateFetchPage(url), nil
+}
+
+type HTTPFetcher struct {
+	Client    *http.Client
+	UserAgent string
+}
+
+func (hf *HTTPFetcher) Fetch(url string) (WebPage, error) {
+	return hf.FetchContext(context.Background(), url)
+}
+
+func (hf *HTTPFetcher) FetchContext(ctx context.Context, url string) (WebPage, error) {
+	return fetchHTTP(ctx, hf.Client, hf.UserAgent, url)
+}
+
+func NewWebCrawler(maxDepth int, fetcher ...Fetcher) *WebCrawler {
+	wc := &WebCrawler{
+		queue:       make([]WebPage, 0),
+		visited:     make(map[string]bool),
+		maxDepth:    maxDepth,
+		crawledData: make([]WebPage, 0),
+		userAgent:   "RealWorldCrawler",
+		robotsTxt:   make(map[string]*robotsEntry),
+		lastFetch:   make(map[string]time.Time),
+		fetcher:     mockFetcher{},
+	}
+	if len(fetcher) > 0 && fetcher[0] != nil {
+		wc.fetcher = fetcher[0]
+	}
+	return wc
+}
+
+func (wc *WebCrawler) UseHTTP(client *http.Client) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	
+	wc.mu.Lock()
+	defer wc.mu.Unlock()
+	
+	wc.fetcher = &HTTPFetcher{Client: client, UserAgent: wc.userAgent}
+}
+
+func fetchHTTP(ctx context.Context, client *http.Client, userAgent, rawURL string) (WebPage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return WebPage{}, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	
+	resp, err := client.Do(req)
+	if err != nil {
+		return WebPage{}, err
+	}
+	defer resp.Body.Close()
+	
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return WebPage{}, err
+	}
+	
+	page := WebPage{
+		URL:        rawURL,
+		Content:    string(body),
+		Links:      []string{},
+		StatusCode: resp.StatusCode,
+	}
+	if strings.Contains(resp.Header.Get("Content-Type"), "html") {
+		page.Links = extractLinks(rawURL, body)
+	}
+	return page, nil
+}
+
+var hrefPattern = regexp.MustCompile(`(?is)<a\s[^>]*?\bhref\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+
+func extractLinks(base string, body []byte) []string {
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return nil
+	}
+	
+	links := make([]string, 0)
+	for _, match := range hrefPattern.FindAllSubmatch(body, -1) {
+		href := strings.TrimSpace(string(match[1]) + string(match[2]) + string(match[3]))
+		lower := strings.ToLower(href)
+		if href == "" || strings.HasPrefix(href, "#") ||
+			strings.HasPrefix(lower, "mailto:") || strings.HasPrefix(lower, "javascript:") {
+			continue
+		}
+		
+		ref, err := url.Parse(href)
+		if err != nil {
+			continue
+		}
+		resolved := baseURL.ResolveReference(ref)
+		if resolved.Scheme != "http" && resolved.Scheme != "https" {
+			continue
+		}
+		links = append(links, resolved.String())
+	}
+	return links
+}
+
+func (wc *WebCrawler) SetRetry(maxRetries int, baseDelay time.Duration) {
+	wc.mu.Lock()
+	defer wc.mu.Unlock()
+	
+	wc.maxRetries = maxRetries
+	wc.retryDelay = baseDelay
+}
+
+func (wc *WebCrawler) fetchContext(ctx context.Context, url string) (WebPage, error) {
+	wc.mu.RLock()
+	fetcher := wc.fetcher
+	wc.mu.RUnlock()
+	
+	if cf, ok := fetcher.(contextFetcher); ok {
+		return cf.FetchContext(ctx, url)
+	}
+	
+	type fetchResult struct {
+		page WebPage
+		err  error
+	}
+	done := make(chan fetchResult, 1)
+	go func() {
+		page, err := fetcher.Fetch(url)
+		done <- fetchResult{page, err}
+	}()
+	select {
+	case result := <-done:
+		return result.page, result.err
+	case <-ctx.Done():
+		return WebPage{}, ctx.Err()
+	}
+}
+
+func (wc *WebCrawler) fetchWithRetry(ctx context.Context, url string) (WebPage, error) {
+	wc.mu.RLock()
+	maxRetries, delay := wc.maxRetries, wc.retryDelay
+	wc.mu.RUnlock()
+	
+	for attempt := 0; ; attempt++ {
+		wc.waitForHost(url)
+		fetchedAt := time.Now()
+		page, err := wc.fetchContext(ctx, url)
+		if ctx.Err() != nil {
+			return WebPage{}, ctx.Err()
+		}
+		if err == nil && page.StatusCode < 500 {
+			page.FetchedAt = fetchedAt
+			return page, nil
+		}
+		if err == nil {
+			err = fmt.Errorf("server returned status %d", page.StatusCode)
+		}
+		if attempt >= maxRetries {
+			return WebPage{}, fmt.Errorf("fetching %s failed after %d attempts: %w", url, attempt+1, err)
+		}
+		
+		backoff := delay << attempt
+		fmt.Printf("  Retrying %s in %v: %v\n", url, backoff, err)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return WebPage{}, ctx.Err()
+		}
+	}
+}
+
+func (wc *WebCrawler) AllowDomains(domains ...string) {
+	wc.mu.Lock()
+	defer wc.mu.Unlock()
+	
+	for _, domain := range domains {
+		wc.allowed = append(wc.allowed, strings.ToLower(domain))
+	}
+}
+
+func (wc *WebCrawler) BlockDomains(domains ...string) {
+	wc.mu.Lock()
+	defer wc.mu.Unlock()
+	
+	for _, domain := range domains {
+		wc.blocked = append(wc.blocked, strings.ToLower(domain))
+	}
+}
+
+func (wc *WebCrawler) domainAllowed(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(parsed.Hostname())
+	matches := func(domain string) bool {
+		return host == domain || strings.HasSuffix(host, "."+domain)
+	}
+	
+	wc.mu.RLock()
+	defer wc.mu.RUnlock()
+	
+	for _, domain := range wc.blocked {
+		if matches(domain) {
+			return false
+		}
+	}
+	if len(wc.allowed) == 0 {
+		return true
+	}
+	for _, domain := range wc.allowed {
+		if matches(domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func (wc *WebCrawler) SetRateLimit(perDomain time.Duration) {
+	wc.mu.Lock()
+	defer wc.mu.Unlock()
+	
+	wc.rateLimit = perDomain
+}
+
+func (wc *WebCrawler) waitForHost(rawURL string) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return
+	}
+	
+	wc.mu.Lock()
+	if wc.rateLimit <= 0 {
+		wc.mu.Unlock()
+		return
+	}
+	next := time.Now()
+	if last, ok := wc.lastFetch[parsed.Host]; ok && last.Add(wc.rateLimit).After(next) {
+		next = last.Add(wc.rateLimit)
+	}
+	wc.lastFetch[parsed.Host] = next
+	wc.mu.Unlock()
+	
+	time.Sleep(time.Until(next))
+}
+
+func NewWebCrawlerLimited(maxDepth, maxPages int) *WebCrawler {
+	wc := NewWebCrawler(maxDepth)
+	wc.maxPages = maxPages
+	return wc
+}
+
+func (wc *WebCrawler) SetUserAgent(userAgent string) {
+	wc.mu.Lock()
+	defer wc.mu.Unlock()
+	
+	wc.userAgent = userAgent
+	wc.robotsTxt = make(map[string]*robotsEntry)
+	if hf, ok := wc.fetcher.(*HTTPFetcher); ok {
+		wc.fetcher = &HTTPFetcher{Client: hf.Client, UserAgent: userAgent}
+	}
+}
+
+type robotsEntry struct {
+	ready chan struct{}
+	rules []string
+}
+
+func (wc *WebCrawler) allowedByRobots(ctx context.Context, rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return true
+	}
+	
+	wc.mu.Lock()
+	entry, cached := wc.robotsTxt[parsed.Host]
+	if !cached {
+		entry = &robotsEntry{ready: make(chan struct{})}
+		wc.robotsTxt[parsed.Host] = entry
+	}
+	userAgent := wc.userAgent
+	wc.mu.Unlock()
+	
+	if !cached {
+		robots, err := wc.fetchWithRetry(ctx, parsed.Scheme+"://"+parsed.Host+"/robots.txt")
+		if err == nil && robots.StatusCode < 400 {
+			entry.rules = parseRobotsTxt(robots.Content, userAgent)
+		}
+		if ctx.Err() != nil {
+			wc.mu.Lock()
+			if wc.robotsTxt[parsed.Host] == entry {
+				delete(wc.robotsTxt, parsed.Host)
+			}
+			wc.mu.Unlock()
+		}
+		close(entry.ready)
+	}
+	select {
+	case <-entry.ready:
+	case <-ctx.Done():
+	}
+	if ctx.Err() != nil {
+		return false
 	}
 	
-	fmt.Printf("Task added to scheduler: %s (Priority: %d, Duration: %v)\n", 
-		task.Name, task.Priority, task.Duration)
-}
-
-func (cs *CPUScheduler) RunScheduler() {
-	cs.mu.Lock()
-	if cs.isRunning {
-		cs.mu.Unlock()
-		return
+	path := parsed.EscapedPath()
+	if path == "" {
+		path = "/"
 	}
-	cs.isRunning = true
-	cs.mu.Unlock()
-
-	for {
-		cs.mu.Lock()
-		if len(cs.readyQueue) == 0 {
-			cs.isRunning = false
-			cs.mu.Unlock()
-			break
+	for _, disallowed := range entry.rules {
+		if strings.HasPrefix(path, disallowed) {
+			return false
 		}
-		
-		task := cs.readyQueue[0]
-		cs.readyQueue = cs.readyQueue[1:]
-		cs.currentTask = &task
-		cs.mu.Unlock()
-		
-		fmt.Printf("Executing task: %s (Duration: %v)\n", task.Name, task.Duration)
-		time.Sleep(task.Duration)
-		
-		cs.mu.Lock()
-		cs.completedTasks = append(cs.completedTasks, task)
-		cs.currentTask = nil
-		cs.mu.Unlock()
-		
-		fmt.Printf("Task completed: %s\n", task.Name)
 	}
+	return true
 }
 
-func (cs *CPUScheduler) GetStatus() {
-	cs.mu.RLock()
-	defer cs.mu.RUnlock()
+func parseRobotsTxt(content, userAgent string) []string {
+	userAgent = strings.ToLower(userAgent)
+	var specific, wildcard []string
+	matchedSpecific := false
 	
-	fmt.Printf("CPU Scheduler Status:\n")
-	if cs.currentTask != nil {
-		fmt.Printf("  Current: %s\n", cs.currentTask.Name)
-	} else {
-		fmt.Printf("  Current: idle\n")
+	var groupAgents []string
+	inRules := false
+	for _, line := range strings.Split(content, "\n") {
+		if comment := strings.Index(line, "#"); comment >= 0 {
+			line = line[:comment]
+		}
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		
+		switch key {
+		case "user-agent":
+			if inRules {
+				groupAgents = nil
+				inRules = false
+			}
+			groupAgents = append(groupAgents, strings.ToLower(value))
+		case "allow", "disallow":
+			inRules = true
+			for _, agent := range groupAgents {
+				if agent == "*" {
+					if key == "disallow" && value != "" {
+						wildcard = append(wildcard, value)
+					}
+				} else if strings.Contains(userAgent, agent) {
+					matchedSpecific = true
+					if key == "disallow" && value != "" {
+						specific = append(specific, value)
+					}
+				}
+			}
+		}
 	}
 	
-	fmt.Printf("  Ready Queue (%d tasks):\n", len(cs.readyQueue))
-	for i, task := range cs.readyQueue {
-		fmt.Printf("    %d. %s (Priority: %d)\n", i+1, task.Name, task.Priority)
+	if matchedSpecific {
+		return specific
 	}
-	
-	fmt.Printf("  Completed: %d tasks\n", len(cs.completedTasks))
+	return wildcard
 }
 
-type WebPage struct {
-	URL      string
-	Content  string
-	Links    []string
-	Depth    int
-	Visited  bool
+func normalizeURL(rawURL string) (string, error) {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", err
+	}
+	if parsed.Scheme == "" || parsed.Host == "" {
+		return "", fmt.Errorf("not an absolute URL: %q", rawURL)
+	}
+	
+	parsed.Scheme = strings.ToLower(parsed.Scheme)
+	host := strings.ToLower(parsed.Hostname())
+	if port := parsed.Port(); port != "" &&
+		!(parsed.Scheme == "http" && port == "80") && !(parsed.Scheme == "https" && port == "443") {
+		host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	parsed.Host = host
+	
+	parsed.Fragment = ""
+	parsed.RawFragment = ""
+	if parsed.Path != "" {
+		parsed.Path = path.Clean(parsed.Path)
+		if parsed.Path == "/" || parsed.Path == "." {
+			parsed.Path = ""
+		}
+	}
+	parsed.RawPath = ""
+	if parsed.RawQuery != "" {
+		parsed.RawQuery = parsed.Query().Encode()
+	}
+	return parsed.String(), nil
 }
 
-type WebCrawler struct {
-	queue       []WebPage
-	visited     map[string]bool
-	maxDepth    int
-	mu          sync.RWMutex
-	crawledData []WebPage
+func (wc *WebCrawler) AddURL(url string, depth int) {
+	wc.addURL(context.Background(), url, depth)
 }
 
-func NewWebCrawler(maxDepth int) *WebCrawler {
-	return &WebCrawler{
-		queue:       make([]WebPage, 0),
-		visited:     make(map[string]bool),
-		maxDepth:    maxDepth,
-		crawledData: make([]WebPage, 0),
+func (wc *WebCrawler) addURL(ctx context.Context, url string, depth int) {
+	normalized, err := normalizeURL(url)
+	if err != nil {
+		fmt.Printf("Skipping invalid URL %s: %v\n", url, err)
+		return
 	}
-}
-
-func (wc *WebCrawler) AddURL(url string, depth int) {
+	url = normalized
+	
+	if !wc.domainAllowed(url) {
+		fmt.Printf("Skipping filtered domain: %s\n", url)
+		return
+	}
+	if !wc.allowedByRobots(ctx, url) {
+		if ctx.Err() != nil {
+			return
+		}
+		fmt.Printf("Disallowed by robots.txt: %s\n", url)
+		return
+	}
+	
 	wc.mu.Lock()
 	defer wc.mu.Unlock()
 	
 	if wc.visited[url] || depth > wc.maxDepth {
 		return
 	}
+	if wc.maxPages > 0 && len(wc.crawledData) >= wc.maxPages {
+		return
+	}
 	
 	page := WebPage{
 		URL:     url,
@@ -213,7 +1266,7 @@ func (wc *WebCrawler) AddURL(url string, depth int) {
 	fmt.Printf("Added to crawl queue: %s (depth: %d)\n", url, depth)
 }
 
-func (wc *WebCrawler) simulateFetchPage(url string) WebPage {
+func (m mockFetcher) simulateFetchPage(url string) WebPage {
 	time.Sleep(100 * time.Millisecond)
 	
 	mockPages := map[string]WebPage{
@@ -222,6 +1275,11 @@ func (wc *WebCrawler) simulateFetchPage(url string) WebPage {
 			Content: "Welcome to Example.com - Home page content",
 			Links:   []string{"https://example.com/about", "https://example.com/products"},
 		},
+		"https://example.com/robots.txt": {
+			URL:     "https://example.com/robots.txt",
+			Content: "User-agent: *\nDisallow: /team\n\nUser-agent: ArchiveBot\nDisallow: /\n",
+			Links:   []string{},
+		},
 		"https://example.com/about": {
 			URL:     "https://example.com/about",
 			Content: "About us page content",
@@ -230,7 +1288,7 @@ func (wc *WebCrawler) simulateFetchPage(url string) WebPage {
 		"https://example.com/products": {
 			URL:     "https://example.com/products",
 			Content: "Our products page content",
-			Links:   []string{"https://example.com/product/1", "https://example.com/product/2"},
+			Links:   []string{"https://example.com/product/1", "https://example.com/product/2", "https://partner.example.org/offers"},
 		},
 		"https://example.com/contact": {
 			URL:     "https://example.com/contact",
@@ -255,17 +1313,46 @@ func (wc *WebCrawler) simulateFetchPage(url string) WebPage {
 	}
 	
 	if page, exists := mockPages[url]; exists {
+		page.StatusCode = 200
 		return page
 	}
 	
 	return WebPage{
-		URL:     url,
-		Content: "Page not found",
-		Links:   []string{},
+		URL:        url,
+		Content:    "Page not found",
+		Links:      []string{},
+		StatusCode: 404,
+	}
+}
+
+func (wc *WebCrawler) trackElapsed(start time.Time) {
+	wc.mu.Lock()
+	defer wc.mu.Unlock()
+	
+	wc.elapsed += time.Since(start)
+}
+
+func (wc *WebCrawler) Stats() CrawlStats {
+	wc.mu.RLock()
+	defer wc.mu.RUnlock()
+	
+	stats := CrawlStats{
+		PagesCrawled:  len(wc.crawledData),
+		FailedFetches: len(wc.failed),
+		Elapsed:       wc.elapsed,
 	}
+	for _, page := range wc.crawledData {
+		stats.LinksDiscovered += len(page.Links)
+		if page.Depth > stats.MaxDepth {
+			stats.MaxDepth = page.Depth
+		}
+	}
+	return stats
 }
 
 func (wc *WebCrawler) Crawl() {
+	defer wc.trackElapsed(time.Now())
+	
 	for {
 		wc.mu.Lock()
 		if len(wc.queue) == 0 {
@@ -277,22 +1364,182 @@ func (wc *WebCrawler) Crawl() {
 		wc.queue = wc.queue[1:]
 		wc.mu.Unlock()
 		
-		fmt.Printf("Crawling: %s (depth: %d)\n", currentPage.URL, currentPage.Depth)
+		wc.crawlPage(context.Background(), currentPage)
+	}
+}
+
+func (wc *WebCrawler) CrawlDFS() {
+	defer wc.trackElapsed(time.Now())
+	
+	for {
+		wc.mu.Lock()
+		if len(wc.queue) == 0 {
+			wc.mu.Unlock()
+			break
+		}
 		
-		fetchedPage := wc.simulateFetchPage(currentPage.URL)
-		fetchedPage.Depth = currentPage.Depth
-		fetchedPage.Visited = true
+		last := len(wc.queue) - 1
+		currentPage := wc.queue[last]
+		wc.queue = wc.queue[:last]
+		wc.mu.Unlock()
 		
+		wc.crawlPage(context.Background(), currentPage)
+	}
+}
+
+func (wc *WebCrawler) CrawlWithContext(ctx context.Context) {
+	defer wc.trackElapsed(time.Now())
+	
+	for ctx.Err() == nil {
 		wc.mu.Lock()
-		wc.crawledData = append(wc.crawledData, fetchedPage)
+		if len(wc.queue) == 0 {
+			wc.mu.Unlock()
+			return
+		}
+		
+		currentPage := wc.queue[0]
+		wc.queue = wc.queue[1:]
 		wc.mu.Unlock()
 		
-		for _, link := range fetchedPage.Links {
-			wc.AddURL(link, currentPage.Depth+1)
+		if !wc.crawlPage(ctx, currentPage) {
+			wc.mu.Lock()
+			wc.queue = append([]WebPage{currentPage}, wc.queue...)
+			wc.mu.Unlock()
 		}
-		
-		fmt.Printf("  Found %d links on %s\n", len(fetchedPage.Links), currentPage.URL)
 	}
+	fmt.Printf("Crawl stopped: %v\n", ctx.Err())
+}
+
+func (wc *WebCrawler) pageLimitReached() bool {
+	wc.mu.RLock()
+	defer wc.mu.RUnlock()
+	
+	return wc.maxPages > 0 && len(wc.crawledData) >= wc.maxPages
+}
+
+func (wc *WebCrawler) crawlPage(ctx context.Context, currentPage WebPage) bool {
+	if wc.pageLimitReached() {
+		return true
+	}
+	fmt.Printf("Crawling: %s (depth: %d)\n", currentPage.URL, currentPage.Depth)
+	
+	fetchedPage, err := wc.fetchWithRetry(ctx, currentPage.URL)
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		wc.mu.Lock()
+		wc.failed = append(wc.failed, currentPage.URL)
+		wc.mu.Unlock()
+		fmt.Printf("  Failed: %v\n", err)
+		return true
+	}
+	fetchedPage.Depth = currentPage.Depth
+	fetchedPage.Visited = true
+	
+	wc.mu.Lock()
+	if wc.maxPages > 0 && len(wc.crawledData) >= wc.maxPages {
+		wc.mu.Unlock()
+		return true
+	}
+	wc.crawledData = append(wc.crawledData, fetchedPage)
+	wc.mu.Unlock()
+	
+	for _, link := range fetchedPage.Links {
+		if ctx.Err() != nil {
+			break
+		}
+		wc.addURL(ctx, link, currentPage.Depth+1)
+	}
+	
+	fmt.Printf("  Found %d links on %s\n", len(fetchedPage.Links), currentPage.URL)
+	return true
+}
+
+func (wc *WebCrawler) CrawlConcurrent(workers int) {
+	defer wc.trackElapsed(time.Now())
+	
+	if workers <= 0 {
+		workers = 1
+	}
+	
+	idle := sync.NewCond(&wc.mu)
+	active := 0
+	
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				wc.mu.Lock()
+				for len(wc.queue) == 0 && active > 0 {
+					idle.Wait()
+				}
+				if len(wc.queue) == 0 {
+					idle.Broadcast()
+					wc.mu.Unlock()
+					return
+				}
+				
+				currentPage := wc.queue[0]
+				wc.queue = wc.queue[1:]
+				active++
+				wc.mu.Unlock()
+				
+				wc.crawlPage(context.Background(), currentPage)
+				
+				wc.mu.Lock()
+				active--
+				idle.Broadcast()
+				wc.mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+type CrawlResult struct {
+	URL           string   `json:"url"`
+	Depth         int      `json:"depth"`
+	ContentLength int      `json:"content_length"`
+	Links         []string `json:"links"`
+}
+
+func (wc *WebCrawler) SaveResults(path string) error {
+	wc.mu.RLock()
+	results := make([]CrawlResult, 0, len(wc.crawledData))
+	for _, page := range wc.crawledData {
+		results = append(results, CrawlResult{
+			URL:           page.URL,
+			Depth:         page.Depth,
+			ContentLength: len(page.Content),
+			Links:         page.Links,
+		})
+	}
+	wc.mu.RUnlock()
+	
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding crawl results: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("writing crawl results: %w", err)
+	}
+	return nil
+}
+
+func LoadResults(path string) ([]CrawlResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading crawl results: %w", err)
+	}
+	
+	var results []CrawlResult
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("decoding crawl results: %w", err)
+	}
+	return results, nil
 }
 
 func (wc *WebCrawler) GetResults() {
@@ -309,6 +1556,13 @@ func (wc *WebCrawler) GetResults() {
 		fmt.Printf("    Content: %s\n", contentPreview)
 		fmt.Printf("    Links found: %d\n", len(page.Links))
 	}
+	
+	if len(wc.failed) > 0 {
+		fmt.Printf("Failed URLs (%d):\n", len(wc.failed))
+		for _, url := range wc.failed {
+			fmt.Printf("  %s\n", url)
+		}
+	}
 }
 
 func demonstrateQueues() {
@@ -316,19 +1570,50 @@ func demonstrateQueues() {
 	printQueue := NewPrintQueue()
 	
 	jobs := []PrintJob{
-		{1, "Resume.pdf", 2, 1, "alice"},
-		{2, "Report.docx", 10, 3, "bob"},
-		{3, "Invoice.pdf", 1, 2, "charlie"},
-		{4, "Manual.pdf", 50, 1, "david"},
-		{5, "Presentation.pptx", 15, 3, "eve"},
+		{ID: 1, Document: "Resume.pdf", Pages: 2, Priority: 1, UserID: "alice"},
+		{ID: 2, Document: "Report.docx", Pages: 10, Priority: 3, UserID: "bob"},
+		{ID: 3, Document: "Invoice.pdf", Pages: 1, Priority: 2, UserID: "charlie"},
+		{ID: 4, Document: "Manual.pdf", Pages: 50, Priority: 1, UserID: "david"},
+		{ID: 5, Document: "Presentation.pptx", Pages: 15, Priority: 3, UserID: "eve"},
 	}
 	
 	for _, job := range jobs {
-		printQueue.AddJob(job)
+		if err := printQueue.AddJob(job); err != nil {
+			fmt.Printf("Rejected: %v\n", err)
+		}
+	}
+	
+	printQueue.GetStatus()
+	fmt.Printf("Pending pages: %d\n", printQueue.PendingPages())
+	
+	fmt.Println("\nCancelling jobs:")
+	for _, id := range []int{2, 3, 99} {
+		fmt.Printf("  Cancel job %d: %v\n", id, printQueue.CancelJob(id))
 	}
 	
+	fmt.Println("\nBumping Manual.pdf to priority 5:")
+	printQueue.Reprioritize(4, 5)
 	printQueue.GetStatus()
 	
+	fmt.Printf("\nEstimated wait at %d pages/minute:\n", printQueue.PagesPerMinute)
+	for _, id := range []int{4, 5, 1, 99} {
+		if wait, err := printQueue.EstimatedWait(id); err != nil {
+			fmt.Printf("  No estimate for job %d: %v\n", id, err)
+		} else {
+			fmt.Printf("  Job %d will print in ~%v\n", id, wait)
+		}
+	}
+	stalled := NewPrintQueue()
+	stalled.PagesPerMinute = 0
+	stalled.AddJob(PrintJob{ID: 1, Document: "Memo.pdf", Pages: 2, Priority: 1, UserID: "user1"})
+	if _, err := stalled.EstimatedWait(1); err != nil {
+		fmt.Printf("  No estimate on a stalled printer: %v\n", err)
+	}
+	
+	if next := printQueue.Peek(); next != nil {
+		fmt.Printf("\nNext up: %s for %s\n", next.Document, next.UserID)
+	}
+	
 	fmt.Println("\nProcessing print jobs:")
 	for {
 		job := printQueue.ProcessNext()
@@ -337,17 +1622,64 @@ func demonstrateQueues() {
 		}
 		fmt.Printf("Printing: %s (%d pages) for %s\n", job.Document, job.Pages, job.UserID)
 		time.Sleep(500 * time.Millisecond)
+		printQueue.CompleteJob(job.ID)
+	}
+	
+	fmt.Println("\nJob statuses:")
+	for _, job := range jobs {
+		status, _ := printQueue.JobStatus(job.ID)
+		fmt.Printf("  Job %d (%s): %s\n", job.ID, job.Document, status)
+	}
+	
+	fmt.Println("\nEqual-priority jobs print in submission order:")
+	fifoQueue := NewPrintQueue()
+	for i, document := range []string{"First.pdf", "Second.pdf", "Third.pdf"} {
+		fifoQueue.AddJob(PrintJob{ID: 10 + i, Document: document, Pages: 1, Priority: 2, UserID: "frank"})
+	}
+	for job := fifoQueue.ProcessNext(); job != nil; job = fifoQueue.ProcessNext() {
+		fmt.Printf("  Dequeued: %s\n", job.Document)
+	}
+	
+	fmt.Println("\nRemoving all of grace's jobs on logoff:")
+	sharedQueue := NewPrintQueue()
+	for i, userID := range []string{"grace", "heidi", "grace", "ivan", "grace", "heidi"} {
+		sharedQueue.AddJob(PrintJob{ID: 20 + i, Document: fmt.Sprintf("Doc%d.pdf", i+1), Pages: 3, Priority: 1, UserID: userID})
+	}
+	fmt.Printf("Removed %d jobs\n", sharedQueue.RemoveByUser("grace"))
+	sharedQueue.GetStatus()
+	
+	fmt.Println("\nPer-user quotas (alice limited to 20 pages):")
+	quotaQueue := NewPrintQueue()
+	quotaQueue.SetUserQuota("alice", 20)
+	quotaJobs := []PrintJob{
+		{ID: 6, Document: "Thesis.pdf", Pages: 12, Priority: 1, UserID: "alice"},
+		{ID: 7, Document: "Appendix.pdf", Pages: 15, Priority: 1, UserID: "alice"},
+		{ID: 8, Document: "Handbook.pdf", Pages: 30, Priority: 1, UserID: "bob"},
+	}
+	for _, job := range quotaJobs {
+		if err := quotaQueue.AddJob(job); err != nil {
+			fmt.Printf("Rejected: %v\n", err)
+		}
+	}
+	
+	fmt.Println("\nBounded queue (capacity 2):")
+	boundedQueue := NewPrintQueueWithCapacity(2)
+	for _, job := range jobs[:3] {
+		if err := boundedQueue.AddJob(job); err != nil {
+			fmt.Printf("Rejected: %v\n", err)
+		}
 	}
+	boundedQueue.GetStatus()
 
 	fmt.Println("\n=== CPU Task Scheduling Example ===")
 	scheduler := NewCPUScheduler()
 	
 	tasks := []Task{
-		{1, "System Update", 2, 1 * time.Second, time.Time{}},
-		{2, "File Backup", 1, 2 * time.Second, time.Time{}},
-		{3, "Virus Scan", 3, 1500 * time.Millisecond, time.Time{}},
-		{4, "Email Sync", 2, 800 * time.Millisecond, time.Time{}},
-		{5, "Database Cleanup", 1, 1200 * time.Millisecond, time.Time{}},
+		{ID: 1, Name: "System Update", Priority: 2, Duration: 1 * time.Second},
+		{ID: 2, Name: "File Backup", Priority: 1, Duration: 2 * time.Second},
+		{ID: 3, Name: "Virus Scan", Priority: 3, Duration: 1500 * time.Millisecond},
+		{ID: 4, Name: "Email Sync", Priority: 2, Duration: 800 * time.Millisecond},
+		{ID: 5, Name: "Database Cleanup", Priority: 1, Duration: 1200 * time.Millisecond},
 	}
 	
 	for _, task := range tasks {
@@ -356,10 +1688,144 @@ func demonstrateQueues() {
 	
 	scheduler.GetStatus()
 	
+	fmt.Printf("\nCancel Database Cleanup: %v\n", scheduler.CancelTask(5))
+	fmt.Printf("Cancel unknown task: %v\n", scheduler.CancelTask(99))
+	
 	fmt.Println("\nStarting task execution:")
 	scheduler.RunScheduler()
 	
 	scheduler.GetStatus()
+	
+	fmt.Println("\nRound-robin scheduling (quantum 100ms):")
+	roundRobin := NewCPUScheduler()
+	for _, task := range []Task{
+		{ID: 1, Name: "A", Priority: 1, Duration: 300 * time.Millisecond},
+		{ID: 2, Name: "B", Priority: 1, Duration: 100 * time.Millisecond},
+		{ID: 3, Name: "C", Priority: 1, Duration: 200 * time.Millisecond},
+	} {
+		roundRobin.AddTask(task)
+	}
+	roundRobin.RunRoundRobin(0)
+	start := time.Now()
+	roundRobin.RunRoundRobin(100 * time.Millisecond)
+	fmt.Printf("Makespan: %v\n", time.Since(start).Round(10*time.Millisecond))
+	roundRobin.GetStatus()
+	
+	fmt.Println("\nGantt chart with an idle gap:")
+	timeline := NewCPUScheduler()
+	timeline.AddTask(Task{ID: 1, Name: "A", Priority: 1, Duration: 300 * time.Millisecond})
+	timeline.AddTask(Task{ID: 2, Name: "B", Priority: 1, Duration: 200 * time.Millisecond})
+	timeline.RunRoundRobin(100 * time.Millisecond)
+	time.Sleep(200 * time.Millisecond)
+	timeline.AddTask(Task{ID: 3, Name: "C", Priority: 1, Duration: 200 * time.Millisecond})
+	timeline.RunScheduler()
+	fmt.Println(timeline.GanttChart())
+	
+	fmt.Println("\nStopping the scheduler with a context:")
+	service := NewCPUScheduler()
+	for i, name := range []string{"Index Rebuild", "Log Rotation", "Cache Warmup"} {
+		service.AddTask(Task{ID: i + 1, Name: name, Priority: 1, Duration: 200 * time.Millisecond})
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	service.RunWithContext(ctx)
+	cancel()
+	service.GetStatus()
+	resumed := time.Now()
+	service.RunScheduler()
+	fmt.Printf("Resumed the remaining work in %v\n", time.Since(resumed).Round(50*time.Millisecond))
+	
+	fmt.Println("\nEarliest-deadline-first vs priority scheduling:")
+	realTimeTasks := func() []Task {
+		now := time.Now()
+		return []Task{
+			{ID: 1, Name: "Telemetry Upload", Priority: 3, Duration: 200 * time.Millisecond, Deadline: now.Add(500 * time.Millisecond)},
+			{ID: 2, Name: "Sensor Read", Priority: 2, Duration: 100 * time.Millisecond, Deadline: now.Add(150 * time.Millisecond)},
+			{ID: 3, Name: "Motor Control", Priority: 1, Duration: 100 * time.Millisecond, Deadline: now.Add(300 * time.Millisecond)},
+		}
+	}
+	byPriority := NewCPUScheduler()
+	for _, task := range realTimeTasks() {
+		byPriority.AddTask(task)
+	}
+	byPriority.RunScheduler()
+	edf := NewCPUScheduler()
+	for _, task := range realTimeTasks() {
+		edf.AddTask(task)
+	}
+	edf.RunEDF()
+	fmt.Printf("Missed deadlines - priority: %d, EDF: %d\n",
+		byPriority.Metrics().MissedDeadlines, edf.Metrics().MissedDeadlines)
+	
+	fmt.Println("\nCompletion callbacks:")
+	notifying := NewCPUScheduler()
+	for i, name := range []string{"Compile", "Link", "Package"} {
+		notifying.AddTask(Task{ID: i + 1, Name: name, Priority: 3 - i, Duration: 50 * time.Millisecond})
+	}
+	finished := 0
+	notifying.OnComplete(func(task Task) {
+		finished++
+		fmt.Printf("  Progress: %d/3 (%s done)\n", finished, task.Name)
+	})
+	notifying.OnComplete(func(task Task) {
+		notifying.GetStatus()
+	})
+	notifying.RunScheduler()
+	
+	fmt.Println("\nNon-blocking start:")
+	background := NewCPUScheduler()
+	background.AddTask(Task{ID: 1, Name: "Thumbnail Generation", Priority: 1, Duration: 100 * time.Millisecond})
+	type startResult struct {
+		done <-chan struct{}
+		err  error
+	}
+	starts := make(chan startResult, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			done, err := background.Start()
+			starts <- startResult{done, err}
+		}()
+	}
+	var running <-chan struct{}
+	for i := 0; i < 2; i++ {
+		if result := <-starts; result.err != nil {
+			fmt.Printf("Second Start rejected: %v\n", result.err)
+		} else {
+			running = result.done
+		}
+	}
+	background.AddTask(Task{ID: 2, Name: "Metadata Extraction", Priority: 1, Duration: 100 * time.Millisecond})
+	<-running
+	fmt.Println("Background run finished")
+	
+	fmt.Println("\nPreemptive priority scheduling:")
+	preemptive := NewCPUScheduler()
+	preemptive.AddTask(Task{ID: 1, Name: "Batch Report", Priority: 1, Duration: 300 * time.Millisecond})
+	done := make(chan struct{})
+	go func() {
+		preemptive.RunPreemptive()
+		close(done)
+	}()
+	time.Sleep(100 * time.Millisecond)
+	preemptive.AddTask(Task{ID: 2, Name: "Interrupt Handler", Priority: 5, Duration: 100 * time.Millisecond})
+	<-done
+	
+	fmt.Println("\nScheduling metrics for two tasks (expected wait 50ms, turnaround 200ms):")
+	measured := NewCPUScheduler()
+	measured.AddTask(Task{ID: 1, Name: "Short", Priority: 2, Duration: 100 * time.Millisecond})
+	measured.AddTask(Task{ID: 2, Name: "Long", Priority: 1, Duration: 200 * time.Millisecond})
+	measured.RunScheduler()
+	metrics := measured.Metrics()
+	fmt.Printf("Average wait: %v, average turnaround: %v\n",
+		metrics.AverageWait.Round(10*time.Millisecond), metrics.AverageTurnaround.Round(10*time.Millisecond))
+	
+	fmt.Println("\nMulti-core execution (2 cores):")
+	multiCore := NewCPUScheduler()
+	multiCore.AddTask(Task{ID: 1, Name: "Render Frame", Priority: 1, Duration: 1 * time.Second})
+	multiCore.AddTask(Task{ID: 2, Name: "Encode Audio", Priority: 1, Duration: 1 * time.Second})
+	start = time.Now()
+	multiCore.RunMultiCore(2)
+	fmt.Printf("Makespan: %v\n", time.Since(start).Round(100*time.Millisecond))
+	multiCore.GetStatus()
 
 	fmt.Println("\n=== Web Crawler BFS Example ===")
 	crawler := NewWebCrawler(2)
@@ -370,6 +1836,196 @@ func demonstrateQueues() {
 	crawler.Crawl()
 	
 	crawler.GetResults()
+	stats := crawler.Stats()
+	fmt.Printf("Stats: %d pages, %d links, max depth %d, %d failed, %v elapsed\n",
+		stats.PagesCrawled, stats.LinksDiscovered, stats.MaxDepth, stats.FailedFetches, stats.Elapsed.Round(10*time.Millisecond))
+	
+	resultsPath := os.TempDir() + "/crawl_results.json"
+	if err := crawler.SaveResults(resultsPath); err != nil {
+		fmt.Printf("Save failed: %v\n", err)
+	} else if loaded, err := LoadResults(resultsPath); err != nil {
+		fmt.Printf("Load failed: %v\n", err)
+	} else {
+		fmt.Printf("\nSaved and reloaded %d results from %s\n", len(loaded), resultsPath)
+		for _, result := range loaded[:2] {
+			fmt.Printf("  %s (depth %d, %d bytes, %d links)\n", result.URL, result.Depth, result.ContentLength, len(result.Links))
+		}
+		os.Remove(resultsPath)
+	}
+	
+	fmt.Println("\nCrawling as ArchiveBot, which robots.txt disallows entirely:")
+	archiveBot := NewWebCrawler(2)
+	archiveBot.SetUserAgent("ArchiveBot")
+	archiveBot.AddURL("https://example.com", 0)
+	archiveBot.Crawl()
+	fmt.Printf("Crawled %d pages\n", len(archiveBot.crawledData))
+	
+	fmt.Println("\nCrawling only within example.com:")
+	scopedCrawler := NewWebCrawler(2)
+	scopedCrawler.AllowDomains("example.com")
+	scopedCrawler.AddURL("https://example.com", 0)
+	scopedCrawler.Crawl()
+	fmt.Printf("Crawled %d pages\n", len(scopedCrawler.crawledData))
+	
+	fmt.Println("\nCrawling at most 3 pages:")
+	limitedCrawler := NewWebCrawlerLimited(2, 3)
+	limitedCrawler.AddURL("https://example.com", 0)
+	limitedCrawler.Crawl()
+	fmt.Printf("Crawled %d pages\n", len(limitedCrawler.crawledData))
+	
+	fmt.Println("\nCrawling at most 3 pages when one fetch fails:")
+	lossyCrawler := NewWebCrawlerLimited(2, 3)
+	lossyCrawler.fetcher = FetcherFunc(func(url string) (WebPage, error) {
+		if url == "https://example.com/about" {
+			return WebPage{}, fmt.Errorf("connection refused")
+		}
+		return mockFetcher{}.Fetch(url)
+	})
+	lossyCrawler.AddURL("https://example.com", 0)
+	lossyCrawler.CrawlConcurrent(3)
+	fmt.Printf("Crawled %d pages, %d failed\n", len(lossyCrawler.crawledData), len(lossyCrawler.failed))
+	
+	fmt.Println("\nRetrying transient failures with exponential backoff:")
+	attempts := make(map[string]int)
+	flakyCrawler := NewWebCrawler(1, FetcherFunc(func(url string) (WebPage, error) {
+		attempts[url]++
+		switch {
+		case url == "https://example.com/about" && attempts[url] <= 2:
+			return WebPage{URL: url, StatusCode: 503}, nil
+		case url == "https://example.com/products":
+			return WebPage{}, fmt.Errorf("connection reset by peer")
+		}
+		return mockFetcher{}.Fetch(url)
+	}))
+	flakyCrawler.SetRetry(3, 50*time.Millisecond)
+	flakyCrawler.AddURL("https://example.com", 0)
+	flakyCrawler.Crawl()
+	flakyCrawler.GetResults()
+	fmt.Printf("Stats: %+v\n", flakyCrawler.Stats())
+	
+	fmt.Println("\nExtracting links from HTML:")
+	sampleHTML := []byte(`<html><body>
+		<a href="/docs/intro">Intro</a>
+		<A HREF='guide.html'>Guide</A>
+		<a class="ext" href=https://golang.org/>Go</a>
+		<a href="#top">Top</a>
+		<a href="mailto:team@example.com">Mail</a>
+		<a href="javascript:void(0)">Menu</a>
+	</body></html>`)
+	for _, link := range extractLinks("https://example.com/docs/", sampleHTML) {
+		fmt.Printf("  %s\n", link)
+	}
+	
+	fmt.Println("\nCrawling an in-memory HTML site:")
+	site := map[string]string{
+		"/":             `<a href="/blog">Blog</a> <a href="mailto:owner@example.com">Contact</a>`,
+		"/blog":         `<a href="/blog/posts/1">First post</a> <a href="#comments">Comments</a>`,
+		"/blog/posts/1": `<a href="../../">Home</a>`,
+	}
+	htmlCrawler := NewWebCrawler(3, FetcherFunc(func(rawURL string) (WebPage, error) {
+		parsed, err := url.Parse(rawURL)
+		if err != nil {
+			return WebPage{}, err
+		}
+		sitePath := parsed.Path
+		if sitePath == "" {
+			sitePath = "/"
+		}
+		body, ok := site[sitePath]
+		if !ok {
+			return WebPage{URL: rawURL, Links: []string{}, StatusCode: 404}, nil
+		}
+		return WebPage{URL: rawURL, Content: body, Links: extractLinks(rawURL, []byte(body)), StatusCode: 200}, nil
+	}))
+	htmlCrawler.AddURL("https://site.test/", 0)
+	htmlCrawler.Crawl()
+	
+	fmt.Println("\nNormalizing URL variants before deduplication:")
+	dedupCrawler := NewWebCrawler(0)
+	for _, variant := range []string{
+		"https://example.com/about",
+		"https://example.com/about/",
+		"https://example.com/about#top",
+		"HTTPS://Example.com:443/team/../about",
+	} {
+		dedupCrawler.AddURL(variant, 0)
+	}
+	dedupCrawler.Crawl()
+	fmt.Printf("Crawled %d pages\n", len(dedupCrawler.crawledData))
+	for _, raw := range []string{"http://[::1]:8080/status/", "https://[::1]:443/status"} {
+		normalized, _ := normalizeURL(raw)
+		fmt.Printf("  %s -> %s\n", raw, normalized)
+	}
+	
+	fmt.Println("\nDepth-first crawl:")
+	dfsCrawler := NewWebCrawler(2)
+	dfsCrawler.AddURL("https://example.com", 0)
+	dfsCrawler.CrawlDFS()
+	for i := range dfsCrawler.crawledData {
+		fmt.Printf("  %d. BFS: %-38s DFS: %s\n", i+1, crawler.crawledData[i].URL, dfsCrawler.crawledData[i].URL)
+	}
+	
+	fmt.Println("\nCancelling a crawl after 250ms:")
+	stoppable := NewWebCrawler(2)
+	stoppable.AddURL("https://example.com", 0)
+	crawlCtx, stopCrawl := context.WithTimeout(context.Background(), 250*time.Millisecond)
+	stoppable.CrawlWithContext(crawlCtx)
+	stopCrawl()
+	fmt.Printf("Crawled %d pages, %d left in queue\n", len(stoppable.crawledData), len(stoppable.queue))
+	
+	fmt.Println("\nCrawling a link graph with a cycle:")
+	cycle := map[string][]string{
+		"https://a.test": {"https://b.test"},
+		"https://b.test": {"https://c.test"},
+		"https://c.test": {"https://a.test"},
+	}
+	cycleCrawler := NewWebCrawler(10, FetcherFunc(func(url string) (WebPage, error) {
+		links, ok := cycle[url]
+		if !ok {
+			return WebPage{URL: url, StatusCode: 404}, nil
+		}
+		return WebPage{URL: url, Content: "node " + url, Links: links, StatusCode: 200}, nil
+	}))
+	cycleCrawler.AddURL("https://a.test", 0)
+	cycleCrawler.Crawl()
+	fmt.Printf("Crawled %d pages\n", len(cycleCrawler.crawledData))
+	
+	fmt.Println("\nConcurrent crawl with 3 workers:")
+	var robotsMu sync.Mutex
+	robotsFetches := make(map[string]int)
+	concurrentCrawler := NewWebCrawler(2, FetcherFunc(func(url string) (WebPage, error) {
+		if strings.HasSuffix(url, "/robots.txt") {
+			robotsMu.Lock()
+			robotsFetches[url]++
+			robotsMu.Unlock()
+		}
+		return mockFetcher{}.Fetch(url)
+	}))
+	concurrentCrawler.AddURL("https://example.com", 0)
+	start = time.Now()
+	concurrentCrawler.CrawlConcurrent(3)
+	fmt.Printf("Crawled %d pages in %v\n", len(concurrentCrawler.crawledData), time.Since(start).Round(100*time.Millisecond))
+	fmt.Printf("robots.txt fetches per host: %v\n", robotsFetches)
+	
+	fmt.Println("\nConcurrent crawl limited to one request per 250ms per domain:")
+	politeCrawler := NewWebCrawler(2)
+	politeCrawler.SetRateLimit(250 * time.Millisecond)
+	politeCrawler.AddURL("https://example.com", 0)
+	politeCrawler.CrawlConcurrent(3)
+	fetchTimes := make([]time.Time, 0, len(politeCrawler.crawledData))
+	for _, page := range politeCrawler.crawledData {
+		if parsed, err := url.Parse(page.URL); err == nil && parsed.Host == "example.com" {
+			fetchTimes = append(fetchTimes, page.FetchedAt)
+		}
+	}
+	sort.Slice(fetchTimes, func(i, j int) bool { return fetchTimes[i].Before(fetchTimes[j]) })
+	minGap := time.Duration(0)
+	for i := 1; i < len(fetchTimes); i++ {
+		if gap := fetchTimes[i].Sub(fetchTimes[i-1]); i == 1 || gap < minGap {
+			minGap = gap
+		}
+	}
+	fmt.Printf("Smallest gap between fetches to example.com: %v\n", minGap.Round(10*time.Millisecond))
 }
 
 func main() {
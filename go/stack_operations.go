@@ -1,8 +1,11 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -116,6 +119,7 @@ type CallFrame struct {
 	LocalVars    map[string]interface{}
 	ReturnAddr   int
 	LineNumber   int
+	RecoverFn    func(err error)
 }
 
 type CallStack struct {
@@ -178,9 +182,40 @@ func (cs *CallStack) GetLocalVariable(name string) interface{} {
 	return nil
 }
 
+func (cs *CallStack) SetRecoverHandler(handler func(err error)) {
+	if frame := cs.GetCurrentFrame(); frame != nil {
+		frame.RecoverFn = handler
+	}
+}
+
+func (cs *CallStack) Throw(err error) bool {
+	fmt.Printf("Thrown: %v\n", err)
+	for len(cs.frames) > 0 {
+		frame := cs.GetCurrentFrame()
+		if frame.RecoverFn != nil {
+			fmt.Printf("Caught in: %s()\n", frame.FunctionName)
+			frame.RecoverFn(err)
+			return true
+		}
+		cs.PopFrame()
+	}
+	
+	fmt.Printf("Uncaught: %v\n", err)
+	return false
+}
+
 func (cs *CallStack) PrintStackTrace() {
+	cs.PrintStackTraceLimited(0)
+}
+
+func (cs *CallStack) PrintStackTraceLimited(maxFrames int) {
 	fmt.Println("Stack Trace:")
-	for i := len(cs.frames) - 1; i >= 0; i-- {
+	shown := len(cs.frames)
+	if maxFrames > 0 && maxFrames < shown {
+		shown = maxFrames
+	}
+	
+	for i := len(cs.frames) - 1; i >= len(cs.frames)-shown; i-- {
 		frame := cs.frames[i]
 		fmt.Printf("  at %s() line %d\n", frame.FunctionName, frame.LineNumber)
 		
@@ -191,23 +226,43 @@ func (cs *CallStack) PrintStackTrace() {
 			fmt.Printf("    Local vars: %v\n", frame.LocalVars)
 		}
 	}
+	
+	if hidden := len(cs.frames) - shown; hidden > 0 {
+		fmt.Printf("  ... %d more frames\n", hidden)
+	}
 }
 
 func (cs *CallStack) GetStackDepth() int {
 	return len(cs.frames)
 }
 
+type Reversible interface {
+	Apply()
+	Revert()
+}
+
 type Action struct {
 	Type        string
 	Description string
 	Data        interface{}
 	Timestamp   time.Time
+	Children    []Action
+	Command     Reversible
+	size        int
 }
 
 type UndoRedoSystem struct {
-	undoStack []Action
-	redoStack []Action
-	maxSize   int
+	mu               sync.RWMutex
+	undoStack        []Action
+	redoStack        []Action
+	maxSize          int
+	maxBytes         int
+	memoryUsage      int
+	groupDepth       int
+	groupDescription string
+	groupActions     []Action
+	mergeFunc        func(prev, next Action) (Action, bool)
+	listeners        []func(canUndo, canRedo bool)
 }
 
 func NewUndoRedoSystem(maxSize int) *UndoRedoSystem {
@@ -218,6 +273,45 @@ func NewUndoRedoSystem(maxSize int) *UndoRedoSystem {
 	}
 }
 
+func NewUndoRedoSystemBytes(maxBytes int) *UndoRedoSystem {
+	return &UndoRedoSystem{
+		undoStack: make([]Action, 0),
+		redoStack: make([]Action, 0),
+		maxBytes:  maxBytes,
+	}
+}
+
+func (urs *UndoRedoSystem) SetMaxBytes(maxBytes int) {
+	urs.mu.Lock()
+	defer urs.mu.Unlock()
+	urs.maxBytes = maxBytes
+	urs.evict()
+}
+
+func (urs *UndoRedoSystem) MemoryUsage() int {
+	urs.mu.RLock()
+	defer urs.mu.RUnlock()
+	return urs.memoryUsage
+}
+
+func estimateActionSize(action Action) int {
+	size := len(action.Type) + len(action.Description)
+	if action.Data != nil {
+		if encoded, err := json.Marshal(action.Data); err == nil {
+			size += len(encoded)
+		} else {
+			size += len(fmt.Sprintf("%v", action.Data))
+		}
+	}
+	if action.Command != nil {
+		size += len(fmt.Sprintf("%v", action.Command))
+	}
+	for _, child := range action.Children {
+		size += estimateActionSize(child)
+	}
+	return size
+}
+
 func (urs *UndoRedoSystem) ExecuteAction(actionType, description string, data interface{}) {
 	action := Action{
 		Type:        actionType,
@@ -226,18 +320,184 @@ func (urs *UndoRedoSystem) ExecuteAction(actionType, description string, data in
 		Timestamp:   time.Now(),
 	}
 	
+	defer urs.notifyChange()
+	urs.mu.Lock()
+	defer urs.mu.Unlock()
+	
+	if urs.groupDepth > 0 {
+		if last := len(urs.groupActions) - 1; last >= 0 && urs.mergeFunc != nil {
+			if merged, ok := urs.mergeFunc(urs.groupActions[last], action); ok {
+				urs.groupActions[last] = merged
+				fmt.Printf("Merged (grouped): %s - %s\n", merged.Type, merged.Description)
+				return
+			}
+		}
+		urs.groupActions = append(urs.groupActions, action)
+		fmt.Printf("Executed (grouped): %s - %s\n", actionType, description)
+		return
+	}
+	
+	if last := len(urs.undoStack) - 1; last >= 0 && urs.mergeFunc != nil {
+		if merged, ok := urs.mergeFunc(urs.undoStack[last], action); ok {
+			urs.memoryUsage -= urs.undoStack[last].size
+			urs.undoStack = urs.undoStack[:last]
+			urs.pushAction(merged)
+			fmt.Printf("Merged: %s - %s\n", merged.Type, merged.Description)
+			return
+		}
+	}
+	
+	urs.pushAction(action)
+	
+	fmt.Printf("Executed: %s - %s\n", actionType, description)
+}
+
+func (urs *UndoRedoSystem) OnChange(listener func(canUndo, canRedo bool)) {
+	urs.mu.Lock()
+	defer urs.mu.Unlock()
+	urs.listeners = append(urs.listeners, listener)
+}
+
+func (urs *UndoRedoSystem) notifyChange() {
+	urs.mu.RLock()
+	canUndo := len(urs.undoStack) > 0
+	canRedo := len(urs.redoStack) > 0
+	listeners := append([]func(canUndo, canRedo bool){}, urs.listeners...)
+	urs.mu.RUnlock()
+	
+	for _, listener := range listeners {
+		listener(canUndo, canRedo)
+	}
+}
+
+func (urs *UndoRedoSystem) SetMergeFunc(merge func(prev, next Action) (Action, bool)) {
+	urs.mu.Lock()
+	defer urs.mu.Unlock()
+	urs.mergeFunc = merge
+}
+
+func (urs *UndoRedoSystem) ExecuteReversible(a Reversible) {
+	description := fmt.Sprintf("%T", a)
+	if s, ok := a.(fmt.Stringer); ok {
+		description = s.String()
+	}
+	
+	defer urs.notifyChange()
+	urs.mu.Lock()
+	defer urs.mu.Unlock()
+	
+	a.Apply()
+	
+	action := Action{
+		Type:        "COMMAND",
+		Description: description,
+		Timestamp:   time.Now(),
+		Command:     a,
+	}
+	
+	if urs.groupDepth > 0 {
+		urs.groupActions = append(urs.groupActions, action)
+		fmt.Printf("Executed (grouped): %s - %s\n", action.Type, description)
+		return
+	}
+	
+	urs.pushAction(action)
+	
+	fmt.Printf("Executed: %s - %s\n", action.Type, description)
+}
+
+func applyAction(action Action) {
+	if action.Command != nil {
+		action.Command.Apply()
+	}
+	for _, child := range action.Children {
+		applyAction(child)
+	}
+}
+
+func revertAction(action Action) {
+	for i := len(action.Children) - 1; i >= 0; i-- {
+		revertAction(action.Children[i])
+	}
+	if action.Command != nil {
+		action.Command.Revert()
+	}
+}
+
+func (urs *UndoRedoSystem) pushAction(action Action) {
+	action.size = estimateActionSize(action)
 	urs.undoStack = append(urs.undoStack, action)
+	urs.memoryUsage += action.size
+	urs.evict()
 	
-	if len(urs.undoStack) > urs.maxSize {
+	urs.redoStack = make([]Action, 0)
+}
+
+func (urs *UndoRedoSystem) evict() {
+	for len(urs.undoStack) > 0 {
+		overCount := urs.maxSize > 0 && len(urs.undoStack) > urs.maxSize
+		overBytes := urs.maxBytes > 0 && urs.memoryUsage > urs.maxBytes
+		if !overCount && !overBytes {
+			return
+		}
+		urs.memoryUsage -= urs.undoStack[0].size
 		urs.undoStack = urs.undoStack[1:]
 	}
+}
+
+func (urs *UndoRedoSystem) BeginGroup(description string) {
+	urs.mu.Lock()
+	defer urs.mu.Unlock()
 	
-	urs.redoStack = make([]Action, 0)
+	urs.groupDepth++
+	if urs.groupDepth > 1 {
+		return
+	}
 	
-	fmt.Printf("Executed: %s - %s\n", actionType, description)
+	urs.groupDescription = description
+	urs.groupActions = make([]Action, 0)
+	fmt.Printf("Began group: %s\n", description)
+}
+
+func (urs *UndoRedoSystem) EndGroup() {
+	defer urs.notifyChange()
+	urs.mu.Lock()
+	defer urs.mu.Unlock()
+	
+	if urs.groupDepth == 0 {
+		return
+	}
+	
+	urs.groupDepth--
+	if urs.groupDepth > 0 {
+		return
+	}
+	
+	if len(urs.groupActions) == 0 {
+		fmt.Printf("Ended empty group: %s\n", urs.groupDescription)
+		return
+	}
+	
+	group := Action{
+		Type:        "GROUP",
+		Description: urs.groupDescription,
+		Timestamp:   time.Now(),
+		Children:    urs.groupActions,
+	}
+	urs.groupActions = nil
+	urs.pushAction(group)
+	
+	fmt.Printf("Ended group: %s (%d actions)\n", group.Description, len(group.Children))
 }
 
 func (urs *UndoRedoSystem) Undo() *Action {
+	defer urs.notifyChange()
+	urs.mu.Lock()
+	defer urs.mu.Unlock()
+	return urs.undo()
+}
+
+func (urs *UndoRedoSystem) undo() *Action {
 	if len(urs.undoStack) == 0 {
 		fmt.Println("Nothing to undo")
 		return nil
@@ -246,7 +506,9 @@ func (urs *UndoRedoSystem) Undo() *Action {
 	lastIndex := len(urs.undoStack) - 1
 	action := urs.undoStack[lastIndex]
 	urs.undoStack = urs.undoStack[:lastIndex]
+	urs.memoryUsage -= action.size
 	
+	revertAction(action)
 	urs.redoStack = append(urs.redoStack, action)
 	
 	fmt.Printf("Undid: %s - %s\n", action.Type, action.Description)
@@ -254,6 +516,13 @@ func (urs *UndoRedoSystem) Undo() *Action {
 }
 
 func (urs *UndoRedoSystem) Redo() *Action {
+	defer urs.notifyChange()
+	urs.mu.Lock()
+	defer urs.mu.Unlock()
+	return urs.redo()
+}
+
+func (urs *UndoRedoSystem) redo() *Action {
 	if len(urs.redoStack) == 0 {
 		fmt.Println("Nothing to redo")
 		return nil
@@ -263,13 +532,65 @@ func (urs *UndoRedoSystem) Redo() *Action {
 	action := urs.redoStack[lastIndex]
 	urs.redoStack = urs.redoStack[:lastIndex]
 	
+	applyAction(action)
 	urs.undoStack = append(urs.undoStack, action)
+	urs.memoryUsage += action.size
+	urs.evict()
 	
 	fmt.Printf("Redid: %s - %s\n", action.Type, action.Description)
 	return &action
 }
 
+func (urs *UndoRedoSystem) UndoN(n int) []*Action {
+	defer urs.notifyChange()
+	urs.mu.Lock()
+	defer urs.mu.Unlock()
+	
+	actions := []*Action{}
+	for i := 0; i < n && len(urs.undoStack) > 0; i++ {
+		actions = append(actions, urs.undo())
+	}
+	return actions
+}
+
+func (urs *UndoRedoSystem) RedoN(n int) []*Action {
+	defer urs.notifyChange()
+	urs.mu.Lock()
+	defer urs.mu.Unlock()
+	
+	actions := []*Action{}
+	for i := 0; i < n && len(urs.redoStack) > 0; i++ {
+		actions = append(actions, urs.redo())
+	}
+	return actions
+}
+
+func (urs *UndoRedoSystem) PeekUndo() *Action {
+	urs.mu.RLock()
+	defer urs.mu.RUnlock()
+	
+	if len(urs.undoStack) == 0 {
+		return nil
+	}
+	action := urs.undoStack[len(urs.undoStack)-1]
+	return &action
+}
+
+func (urs *UndoRedoSystem) PeekRedo() *Action {
+	urs.mu.RLock()
+	defer urs.mu.RUnlock()
+	
+	if len(urs.redoStack) == 0 {
+		return nil
+	}
+	action := urs.redoStack[len(urs.redoStack)-1]
+	return &action
+}
+
 func (urs *UndoRedoSystem) GetHistory() {
+	urs.mu.RLock()
+	defer urs.mu.RUnlock()
+	
 	fmt.Printf("Undo/Redo System Status:\n")
 	fmt.Printf("  Undo stack: %d actions\n", len(urs.undoStack))
 	fmt.Printf("  Redo stack: %d actions\n", len(urs.redoStack))
@@ -290,19 +611,50 @@ func (urs *UndoRedoSystem) GetHistory() {
 }
 
 func (urs *UndoRedoSystem) CanUndo() bool {
+	urs.mu.RLock()
+	defer urs.mu.RUnlock()
 	return len(urs.undoStack) > 0
 }
 
 func (urs *UndoRedoSystem) CanRedo() bool {
+	urs.mu.RLock()
+	defer urs.mu.RUnlock()
 	return len(urs.redoStack) > 0
 }
 
 func (urs *UndoRedoSystem) ClearHistory() {
+	defer urs.notifyChange()
+	urs.mu.Lock()
+	defer urs.mu.Unlock()
+	
 	urs.undoStack = make([]Action, 0)
 	urs.redoStack = make([]Action, 0)
+	urs.memoryUsage = 0
 	fmt.Println("Cleared all undo/redo history")
 }
 
+type TextBuffer struct {
+	content string
+}
+
+type InsertTextCommand struct {
+	buffer   *TextBuffer
+	text     string
+	position int
+}
+
+func (c *InsertTextCommand) Apply() {
+	c.buffer.content = c.buffer.content[:c.position] + c.text + c.buffer.content[c.position:]
+}
+
+func (c *InsertTextCommand) Revert() {
+	c.buffer.content = c.buffer.content[:c.position] + c.buffer.content[c.position+len(c.text):]
+}
+
+func (c *InsertTextCommand) String() string {
+	return fmt.Sprintf("Insert '%s' at %d", c.text, c.position)
+}
+
 func simulateRecursiveFunction(cs *CallStack, n int, depth int) int {
 	cs.PushFrame("factorial", map[string]interface{}{"n": n}, 100+depth)
 	
@@ -365,10 +717,26 @@ func demonstrateStacks() {
 	
 	callStack.PrintStackTrace()
 	
+	fmt.Println("\nTruncated trace (top 2 frames):")
+	callStack.PrintStackTraceLimited(2)
+	
 	fmt.Println("\nUnwinding the stack:")
 	for callStack.GetStackDepth() > 0 {
 		callStack.PopFrame()
 	}
+	
+	fmt.Println("\nSimulating exception propagation (try/catch):")
+	callStack.PushFrame("main", map[string]interface{}{}, 1)
+	callStack.PushFrame("handleRequest", map[string]interface{}{"path": "/orders"}, 20)
+	callStack.SetRecoverHandler(func(err error) {
+		callStack.SetLocalVariable("status", 500)
+		fmt.Printf("  handleRequest recovered: %v\n", err)
+	})
+	callStack.PushFrame("loadOrders", map[string]interface{}{"userId": 123}, 52)
+	callStack.PushFrame("queryDatabase", map[string]interface{}{"table": "orders"}, 88)
+	callStack.PushFrame("openConnection", map[string]interface{}{"host": "db-1"}, 130)
+	callStack.Throw(errors.New("connection refused"))
+	callStack.PrintStackTrace()
 
 	fmt.Println("\n=== Undo/Redo Operations Example ===")
 	undoSystem := NewUndoRedoSystem(10)
@@ -387,6 +755,10 @@ func demonstrateStacks() {
 	undoSystem.Undo()
 	undoSystem.GetHistory()
 	
+	if next := undoSystem.PeekRedo(); next != nil {
+		fmt.Printf("Redo button label: \"Redo: %s\"\n", next.Description)
+	}
+	
 	fmt.Println("\nRedo operations:")
 	undoSystem.Redo()
 	undoSystem.GetHistory()
@@ -396,6 +768,86 @@ func demonstrateStacks() {
 	undoSystem.GetHistory()
 	
 	fmt.Printf("\nCan undo: %t, Can redo: %t\n", undoSystem.CanUndo(), undoSystem.CanRedo())
+	
+	fmt.Println("\nGrouping a find-and-replace into one undo step:")
+	undoSystem.BeginGroup("Replace all 'World' with 'Go'")
+	undoSystem.ExecuteAction("DELETE", "Delete 'World'", map[string]interface{}{"position": 6, "count": 5})
+	undoSystem.ExecuteAction("INSERT", "Insert 'Go'", map[string]interface{}{"text": "Go", "position": 6})
+	undoSystem.EndGroup()
+	undoSystem.GetHistory()
+	undoSystem.Undo()
+	undoSystem.GetHistory()
+	
+	fmt.Println("\nReversible commands that really edit a buffer:")
+	buffer := &TextBuffer{}
+	editor := NewUndoRedoSystem(10)
+	editor.OnChange(func(canUndo, canRedo bool) {
+		fmt.Printf("  [toolbar] undo enabled: %t, redo enabled: %t\n", canUndo, canRedo)
+	})
+	editor.ExecuteReversible(&InsertTextCommand{buffer: buffer, text: "Hello", position: 0})
+	editor.ExecuteReversible(&InsertTextCommand{buffer: buffer, text: " World", position: 5})
+	fmt.Printf("Buffer: %q\n", buffer.content)
+	editor.Undo()
+	fmt.Printf("Buffer: %q\n", buffer.content)
+	editor.Redo()
+	fmt.Printf("Buffer: %q\n", buffer.content)
+	
+	fmt.Println("\nUndo 5 steps (only 2 available):")
+	undone := editor.UndoN(5)
+	fmt.Printf("Undid %d actions, buffer: %q\n", len(undone), buffer.content)
+	redone := editor.RedoN(1)
+	fmt.Printf("Redid %d actions, buffer: %q\n", len(redone), buffer.content)
+	fmt.Printf("UndoN(-1) undid %d actions\n", len(editor.UndoN(-1)))
+	
+	fmt.Println("\nBounding history by memory (200 bytes):")
+	bounded := NewUndoRedoSystemBytes(200)
+	bounded.ExecuteAction("PASTE", "Paste large block", map[string]interface{}{"text": strings.Repeat("lorem ipsum ", 10)})
+	fmt.Printf("Memory usage: %d bytes, can undo: %t\n", bounded.MemoryUsage(), bounded.CanUndo())
+	bounded.ExecuteAction("INSERT", "Insert 'x'", map[string]interface{}{"text": "x", "position": 0})
+	bounded.ExecuteAction("PASTE", "Paste another block", map[string]interface{}{"text": strings.Repeat("dolor sit ", 8)})
+	bounded.GetHistory()
+	fmt.Printf("Memory usage: %d bytes\n", bounded.MemoryUsage())
+	bounded.Undo()
+	bounded.SetMaxBytes(150)
+	bounded.Redo()
+	fmt.Printf("After redo under a 150-byte limit: %d bytes\n", bounded.MemoryUsage())
+	
+	fmt.Println("\nCoalescing consecutive typing into one action:")
+	typing := NewUndoRedoSystem(10)
+	typing.SetMergeFunc(func(prev, next Action) (Action, bool) {
+		if prev.Type != "TYPE" || next.Type != "TYPE" || next.Timestamp.Sub(prev.Timestamp) > time.Second {
+			return Action{}, false
+		}
+		text := prev.Data.(string) + next.Data.(string)
+		prev.Data = text
+		prev.Description = fmt.Sprintf("Type '%s'", text)
+		prev.Timestamp = next.Timestamp
+		return prev, true
+	})
+	for _, ch := range []string{"H", "e", "l", "l", "o"} {
+		typing.ExecuteAction("TYPE", fmt.Sprintf("Type '%s'", ch), ch)
+	}
+	typing.ExecuteAction("FORMAT", "Make text italic", map[string]interface{}{"style": "italic"})
+	typing.GetHistory()
+	
+	fmt.Println("\nConcurrent collaborators editing the same history:")
+	shared := NewUndoRedoSystem(50)
+	var wg sync.WaitGroup
+	for user := 1; user <= 3; user++ {
+		wg.Add(1)
+		go func(user int) {
+			defer wg.Done()
+			for i := 0; i < 3; i++ {
+				shared.ExecuteAction("INSERT", fmt.Sprintf("User %d edit %d", user, i), map[string]interface{}{"user": user})
+				if i%2 == 1 {
+					shared.Undo()
+					shared.Redo()
+				}
+			}
+		}(user)
+	}
+	wg.Wait()
+	fmt.Printf("Can undo: %t, Can redo: %t\n", shared.CanUndo(), shared.CanRedo())
 }
 
 func main() {